@@ -0,0 +1,301 @@
+package httphelper
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormTag is a name of struct field tag used by BindForm.
+const FormTag = "form"
+
+// BindForm scans Request.Form and stores values to fields of struct pointed by dst.
+// Each exported field of struct with "form" tag is scanned from form field with name equal to tag value (i.e. Age int `form:"age"`).
+// Fields without "form" tag (or with "-" tag) are skipped.
+// By default fields are optional: if there is no such field in form, struct field leaves untouched, except nullable fields (pointers like *int & sql.Null* types) which are reset to nil & invalid (as for optional fields of ScanFormData).
+// Tag value may contain comma separated options after name.
+// Option "required" makes field required: error with ScanErrorTypeNoSuchField is returned if there is no such field in form (i.e. `form:"age,required"`).
+// Option "default=<value>" defines value assigned to struct field if there is no such field in form (i.e. `form:"size,default=25"`), value can not contain comma.
+// Default values are parsed (using the same rules as form values) before scanning form, so invalid default causes error before any struct field modified.
+// Nested struct fields (which are not of supported types, i.e. time.Time) are scanned recursively, their form field names are prefixed with tag value of nested struct and separator (ScanOptions.NestedSeparator, "." by default, i.e. "address.city").
+// Anonymous embedded structs without "form" tag are flattened: their fields are scanned as fields of outer struct (without prefix).
+// Slices of nested structs are scanned from form fields with indexed names (i.e. "items[0].name", "items[1].name"), elements are ordered by index and missing indices are skipped (so "items[3]" & "items[1]" results in 2 elements).
+// Index should be a decimal number without sign and leading zeros, form fields with other indices (i.e. "items[01].name") are ignored.
+// Slice of nested structs is replaced only if there is at least one form field for it, defaults of its elements are checked while scanning.
+// All other rules are the same as for ScanFormData (so struct field may be of any type supported by ScanFormData).
+// FieldNum in returned ScanError is a index of (top-level) struct field, FieldPath is a path of struct field (i.e. "Address.Zip").
+// If struct field has unsupported type then ScanError with type ScanErrorTypeIncompatibleType is returned, its SubError contains name of struct field.
+// Returned error is of type ScanError or nil (if dst is not a pointer to struct or tag is malformed, plain error is returned).
+// Warning: r.ParseForm should be performed before calling this function.
+func BindForm(r *http.Request, dst interface{}) error {
+	return BindFormWithOptions(r, ScanOptions{}, dst)
+}
+
+// BindFormWithOptions does the same as BindForm but uses given options instead of default ones.
+// If options.AutoParseForm is set then returned error may also be of type ParseFormError.
+func BindFormWithOptions(r *http.Request, options ScanOptions, dst interface{}) error {
+	if err := options.parseForm(r); err != nil {
+		return err
+	}
+	return options.bindForm(options.requestForm(r), multipartFiles(r), dst)
+}
+
+// formTag is a parsed "form" tag.
+type formTag struct {
+	name       string
+	required   bool
+	def        string
+	hasDefault bool
+}
+
+// parseFormTag parses value of "form" tag.
+func parseFormTag(tag string) (formTag, error) {
+	parts := strings.Split(tag, ",")
+	ft := formTag{name: parts[0]}
+	if ft.name == "" {
+		return ft, errors.New("empty name")
+	}
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "required":
+			ft.required = true
+		case strings.HasPrefix(opt, "default="):
+			ft.def = strings.TrimPrefix(opt, "default=")
+			ft.hasDefault = true
+		default:
+			return ft, errors.New("unknown option '" + opt + "'")
+		}
+	}
+	if ft.required && ft.hasDefault {
+		return ft, errors.New("options 'required' and 'default' are mutually exclusive")
+	}
+	return ft, nil
+}
+
+// bindField is a struct field prepared for scanning by bindForm.
+type bindField struct {
+	num   int                 // index of top-level struct field
+	path  string              // path of struct field (i.e. "Address.Zip")
+	sf    reflect.StructField // struct field itself
+	field ScanField           // field to scan
+	slice reflect.Value       // slice of nested structs (if valid then only field.Name is used, it is a form name of slice)
+}
+
+// nestedSeparator returns separator between name of nested struct and name of its field.
+func (o *ScanOptions) nestedSeparator() string {
+	if o.NestedSeparator == "" {
+		return "."
+	}
+	return o.NestedSeparator
+}
+
+// Types used by nestedStruct.
+var (
+	formScannerType     = reflect.TypeOf((*FormScanner)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binUnmarshalerType  = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	urlType             = reflect.TypeOf(url.URL{})
+	ipNetType           = reflect.TypeOf(net.IPNet{})
+	mailAddressType     = reflect.TypeOf(mail.Address{})
+)
+
+// nestedStruct checks if t is a struct which should be scanned recursively (i.e. it is not a struct of supported type like time.Time).
+func nestedStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == urlType || t == ipNetType || t == mailAddressType {
+		return false
+	}
+	if _, _, isNull := nullValue(reflect.New(t).Interface()); isNull {
+		return false
+	}
+	pt := reflect.PtrTo(t)
+	return !pt.Implements(formScannerType) && !pt.Implements(textUnmarshalerType) && !pt.Implements(binUnmarshalerType)
+}
+
+// bindForm scans form for fields of struct pointed by dst.
+func (o *ScanOptions) bindForm(form url.Values, files map[string][]*multipart.FileHeader, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("BindForm: dst should be a non-nil pointer to struct.")
+	}
+
+	// Prepare fields (and check defaults) before scanning
+	fields, err := o.bindFields(v.Elem(), -1, "", "", nil)
+	if err != nil {
+		return err
+	}
+
+	return o.bindScan(form, files, fields)
+}
+
+// bindScan scans form for prepared fields.
+func (o *ScanOptions) bindScan(form url.Values, files map[string][]*multipart.FileHeader, fields []bindField) error {
+	for _, bf := range fields {
+		if bf.slice.IsValid() {
+			if err := o.bindSlice(form, files, bf); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := o.scanField(form, files, bf.num, bf.field); err != nil {
+			return bindError(err.(ScanError), bf.sf, bf.path, "")
+		}
+	}
+	return nil
+}
+
+// bindSlice scans slice of nested structs from form fields with indexed names (i.e. "items[0].name").
+// Elements are ordered by index, missing indices are skipped (so "items[0].name" & "items[5].name" results in slice of 2 elements).
+// If there are no such form fields then slice leaves untouched.
+func (o *ScanOptions) bindSlice(form url.Values, files map[string][]*multipart.FileHeader, bf bindField) error {
+	indices := o.sliceIndices(form, files, bf.field.Name)
+	if len(indices) == 0 {
+		return nil
+	}
+	result := reflect.MakeSlice(bf.slice.Type(), len(indices), len(indices))
+	for j, index := range indices {
+		path := bf.path + "[" + strconv.Itoa(j) + "]."
+		fields, err := o.bindFields(result.Index(j), bf.num, bf.field.Name+"["+index+"]"+o.nestedSeparator(), path, nil)
+		if err != nil {
+			return err
+		}
+		if err := o.bindScan(form, files, fields); err != nil {
+			return err
+		}
+	}
+	if !o.DryRun {
+		bf.slice.Set(result)
+	}
+	return nil
+}
+
+// sliceIndices returns indices (as they are in form, sorted numerically) of form fields with names like "<name>[<index>]<separator>...".
+func (o *ScanOptions) sliceIndices(form url.Values, files map[string][]*multipart.FileHeader, name string) []string {
+	found := make(map[string]int)
+	check := func(key string) {
+		if len(key) <= len(name) || key[len(name)] != '[' || !o.matchName(key[:len(name)], &ScanField{Name: name}) {
+			return
+		}
+		rest := key[len(name)+1:]
+		end := strings.IndexByte(rest, ']')
+		if end <= 0 || !strings.HasPrefix(rest[end+1:], o.nestedSeparator()) {
+			return
+		}
+		index, err := strconv.ParseUint(rest[:end], 10, 31)
+		if err != nil || strconv.FormatUint(index, 10) != rest[:end] { // only canonical indices, so "01" is not a duplicate of "1"
+			return
+		}
+		found[rest[:end]] = int(index)
+	}
+	for key := range form {
+		check(key)
+	}
+	for key := range files {
+		check(key)
+	}
+
+	indices := make([]string, 0, len(found))
+	for index := range found {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return found[indices[i]] < found[indices[j]] })
+	return indices
+}
+
+// bindFields appends fields of struct v (recursively for nested structs) to fields.
+// num is an index of top-level struct field (-1 for top-level struct), prefix is a prefix of form field names and path is a prefix of struct field paths.
+func (o *ScanOptions) bindFields(v reflect.Value, num int, prefix, path string, fields []bindField) ([]bindField, error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldNum := num
+		if fieldNum < 0 {
+			fieldNum = i
+		}
+		tag := sf.Tag.Get(FormTag)
+
+		if sf.Anonymous && tag == "" && nestedStruct(sf.Type) {
+			var err error
+			if fields, err = o.bindFields(v.Field(i), fieldNum, prefix, path+sf.Name+".", fields); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if sf.PkgPath != "" { // unexported
+			continue
+		}
+		if tag == "" || tag == "-" {
+			continue
+		}
+		ft, err := parseFormTag(tag)
+		if err != nil {
+			return nil, errors.New("BindForm: invalid tag of struct field '" + path + sf.Name + "': " + err.Error() + ".")
+		}
+
+		if nestedStruct(sf.Type) {
+			if ft.required || ft.hasDefault {
+				return nil, errors.New("BindForm: invalid tag of struct field '" + path + sf.Name + "': options are not allowed for nested struct.")
+			}
+			if fields, err = o.bindFields(v.Field(i), fieldNum, prefix+ft.name+o.nestedSeparator(), path+sf.Name+".", fields); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if sf.Type.Kind() == reflect.Slice && nestedStruct(sf.Type.Elem()) {
+			if ft.required || ft.hasDefault {
+				return nil, errors.New("BindForm: invalid tag of struct field '" + path + sf.Name + "': options are not allowed for slice of nested structs.")
+			}
+			fields = append(fields, bindField{num: fieldNum, path: path + sf.Name, sf: sf, field: ScanField{Name: prefix + ft.name}, slice: v.Field(i)})
+			continue
+		}
+
+		field := ScanField{Name: prefix + ft.name, Value: v.Field(i).Addr().Interface(), Optional: !ft.required}
+		if ft.hasDefault {
+			def := reflect.New(sf.Type)
+			if err := o.scanDefault(fieldNum, field, ft.def, def.Interface()); err != nil {
+				return nil, bindError(err.(ScanError), sf, path+sf.Name, "default value: ")
+			}
+			field.Default = def.Elem().Interface()
+		}
+		fields = append(fields, bindField{num: fieldNum, path: path + sf.Name, sf: sf, field: field})
+	}
+	return fields, nil
+}
+
+// scanDefault parses default value s of field and stores result to variable pointed by value.
+// Default of slice or array field is parsed as a single form value (so "default=a" for []string results in []string{"a"}).
+func (o *ScanOptions) scanDefault(fieldNum int, field ScanField, s string, value interface{}) error {
+	field.Value = value
+	if sv, isSlice := sliceTarget(&field); isSlice {
+		return o.scanSlice(fieldNum, &field, []string{s}, sv)
+	}
+	if av, isArray := arrayTarget(&field); isArray {
+		return o.scanArray(fieldNum, &field, []string{s}, av)
+	}
+	return o.scanValue(fieldNum, &field, s, value)
+}
+
+// bindError adds struct field information to err: FieldPath is set to path.
+// For incompatible type error SubError is replaced with description of struct field, for incompatible value error SubError is prefixed with prefix.
+func bindError(err ScanError, sf reflect.StructField, path string, prefix string) ScanError {
+	err.FieldPath = path
+	switch err.Type {
+	case ScanErrorTypeIncompatibleType:
+		err.SubError = errors.New("struct field '" + path + "' has unsupported type " + sf.Type.String() + ".")
+	case ScanErrorTypeIncompatibleValue:
+		if prefix != "" && err.SubError != nil {
+			err.SubError = fmt.Errorf("%s%w", prefix, err.SubError)
+		}
+	}
+	return err
+}
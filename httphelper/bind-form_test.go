@@ -0,0 +1,152 @@
+package httphelper
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newFormRequest returns GET request with given query string and parsed form.
+func newFormRequest(t testing.TB, query string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	if err := r.ParseForm(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestBindFormSliceDefault(t *testing.T) {
+	type form struct {
+		Tags []string `form:"tags,default=a"`
+		IDs  []int    `form:"ids,default=7"`
+	}
+	tests := []struct {
+		query string
+		tags  []string
+		ids   []int
+	}{
+		{"", []string{"a"}, []int{7}},
+		{"tags=x&tags=y&ids=1", []string{"x", "y"}, []int{1}},
+	}
+	for _, test := range tests {
+		var f form
+		if err := BindForm(newFormRequest(t, test.query), &f); err != nil {
+			t.Errorf("%q: unexpected error %v", test.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(f.Tags, test.tags) || !reflect.DeepEqual(f.IDs, test.ids) {
+			t.Errorf("%q: expected %v %v, got %v %v", test.query, test.tags, test.ids, f.Tags, f.IDs)
+		}
+	}
+}
+
+func TestBindFormSliceDefaultInvalid(t *testing.T) {
+	var f struct {
+		IDs []int `form:"ids,default=x"`
+	}
+	if err := BindForm(newFormRequest(t, ""), &f); err == nil {
+		t.Error("expected error for invalid default")
+	}
+}
+
+func TestBindFormAbsentOptional(t *testing.T) {
+	var f struct {
+		I int            `form:"i"`
+		P *int           `form:"p"`
+		N sql.NullString `form:"n"`
+	}
+	one := 1
+	f.I, f.P, f.N = 1, &one, sql.NullString{String: "x", Valid: true}
+	if err := BindForm(newFormRequest(t, ""), &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.I != 1 || f.P != nil || f.N.Valid {
+		t.Errorf("expected untouched int & reset nullable fields, got %v %v %+v", f.I, f.P, f.N)
+	}
+}
+
+func TestBindFormEmptyTagName(t *testing.T) {
+	var f struct {
+		I int `form:",required"`
+	}
+	if err := BindForm(newFormRequest(t, "=7"), &f); err == nil {
+		t.Errorf("expected error for empty name in tag, got %v", f.I)
+	}
+}
+
+type bindItem struct {
+	Name string `form:"name,required"`
+	Qty  int    `form:"qty"`
+}
+
+func TestBindFormSliceIndices(t *testing.T) {
+	tests := []struct {
+		query string
+		items []bindItem
+	}{
+		{"items[3].name=c&items[1].name=a&items[1].qty=2", []bindItem{{"a", 2}, {"c", 0}}},
+		{"items[10].name=b&items[2].name=a", []bindItem{{"a", 0}, {"b", 0}}},
+		{"items[1].name=a&items[01].name=x&items[+1].name=y&items[-1].name=z", []bindItem{{"a", 0}}},
+		{"items[0]name=a&items.0.name=b", nil},
+	}
+	for _, test := range tests {
+		var f struct {
+			Items []bindItem `form:"items"`
+		}
+		if err := BindForm(newFormRequest(t, test.query), &f); err != nil {
+			t.Errorf("%q: unexpected error %v", test.query, err)
+		} else if !reflect.DeepEqual(f.Items, test.items) {
+			t.Errorf("%q: expected %v, got %v", test.query, test.items, f.Items)
+		}
+	}
+}
+
+func TestBindFormSliceElementError(t *testing.T) {
+	var f struct {
+		ID    int        `form:"id"`
+		Items []bindItem `form:"items"`
+	}
+	err := BindForm(newFormRequest(t, "items[5].name=a&items[7].name=b&items[7].qty=x"), &f)
+	se, ok := err.(ScanError)
+	if !ok {
+		t.Fatalf("expected ScanError, got %#v", err)
+	}
+	if se.FieldNum != 1 || se.FieldName != "items[7].qty" || se.FieldPath != "Items[1].Qty" || se.Type != ScanErrorTypeIncompatibleValue {
+		t.Errorf("unexpected error %#v", se)
+	}
+
+	err = BindForm(newFormRequest(t, "items[0].qty=1"), &f)
+	if se, ok = err.(ScanError); !ok || se.FieldName != "items[0].name" || se.FieldPath != "Items[0].Name" || se.Type != ScanErrorTypeNoSuchField {
+		t.Errorf("expected missing 'items[0].name' error, got %#v", err)
+	}
+}
+
+func TestBindFormNested(t *testing.T) {
+	type address struct {
+		City string `form:"city"`
+		Zip  int    `form:"zip"`
+	}
+	type base struct {
+		ID int `form:"id"`
+	}
+	var f struct {
+		base
+		Home address `form:"home"`
+		Work address `form:"work"`
+	}
+
+	if err := BindForm(newFormRequest(t, "id=1&home.city=A&home.zip=1&work.city=B"), &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.ID != 1 || f.Home != (address{"A", 1}) || f.Work != (address{"B", 0}) {
+		t.Errorf("unexpected result %+v", f)
+	}
+
+	err := BindFormWithOptions(newFormRequest(t, "work_zip=x"), ScanOptions{NestedSeparator: "_"}, &f)
+	se, ok := err.(ScanError)
+	if !ok || se.FieldNum != 2 || se.FieldName != "work_zip" || se.FieldPath != "Work.Zip" {
+		t.Errorf("expected error for 'work_zip' (Work.Zip), got %#v", err)
+	}
+}
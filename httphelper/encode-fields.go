@@ -0,0 +1,217 @@
+package httphelper
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncodeFields returns values of variables pointed by fields formatted back to strings (in format accepted by ScanFormData), so scanned values may be re-encoded (i.e. for redirect or signed URL).
+// Slice & array fields produce multiple values (or single value joined by Separator if Split is set).
+// Field's Base, Char, Encoding, DurationUnit and time layouts (for ScanTimeField) are respected.
+// Bools are formatted as "on" & "off", use Scanner.Encode to format them using configured tokens.
+// Checkbox bools are omitted if false (so they are scanned back as false).
+// Nil pointers, invalid sql.Null* values, file fields and fields of unsupported types are omitted.
+func EncodeFields(fields ...ScanField) url.Values {
+	var options ScanOptions
+	return options.encodeFields(fields)
+}
+
+// encodeFields formats values of fields.
+func (o *ScanOptions) encodeFields(fields []ScanField) url.Values {
+	v := make(url.Values, len(fields))
+	for i := range fields {
+		field := &fields[i]
+		values := o.formatField(field)
+		if len(values) == 0 {
+			continue
+		}
+		if field.Split && len(values) > 1 {
+			values = []string{strings.Join(values, field.separator())}
+		}
+		v[field.Name] = values
+	}
+	return v
+}
+
+// formatField formats value of field (all elements for slice & array fields).
+func (o *ScanOptions) formatField(field *ScanField) []string {
+	value := field.Value
+	if iv, ok := value.(*interface{}); ok && field.Type != nil { // value with type hint
+		if iv == nil || *iv == nil {
+			return nil
+		}
+		pv := reflect.New(reflect.TypeOf(*iv))
+		pv.Elem().Set(reflect.ValueOf(*iv))
+		value = pv.Interface()
+	}
+	f := *field
+	f.Value = value
+
+	sv, isSlice := sliceTarget(&f)
+	if !isSlice {
+		sv, isSlice = arrayTarget(&f)
+	}
+	if isSlice {
+		values := make([]string, 0, sv.Len())
+		for i := 0; i < sv.Len(); i++ {
+			if s, ok := o.formatValue(&f, sv.Index(i).Addr().Interface()); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	if s, ok := o.formatValue(&f, value); ok {
+		return []string{s}
+	}
+	return nil
+}
+
+// formatValue formats variable pointed by value using parameters from field. It returns false if value can not be formatted.
+func (o *ScanOptions) formatValue(field *ScanField, value interface{}) (string, bool) {
+	if tv, ok := value.(timeValue); ok && tv.value == nil {
+		return "", false
+	}
+	if pv := reflect.ValueOf(value); pv.Kind() == reflect.Ptr && pv.IsNil() {
+		return "", false
+	}
+	if inner, valid, isNull := nullValue(value); isNull {
+		if !*valid {
+			return "", false
+		}
+		value = inner
+	}
+	if field.Char {
+		switch v := value.(type) {
+		case *rune:
+			return string(*v), true
+		case *byte:
+			return string([]byte{*v}), true
+		}
+	}
+	if uv, ok := uuidTarget(value); ok && field.UUID {
+		var u [16]byte
+		reflect.Copy(reflect.ValueOf(&u).Elem(), uv)
+		return formatUUID(u), true
+	}
+	if b, ok := value.(*[]byte); ok && field.Encoding != ScanEncodingNone {
+		return field.Encoding.encode(*b), true
+	}
+	if b, ok := value.(*bool); ok && field.Checkbox {
+		// Unchecked checkbox is not submitted at all, checked one is submitted with any non-empty value.
+		if !*b {
+			return "", false
+		}
+		return o.formatBool(true), true
+	}
+	if d, ok := value.(*time.Duration); ok && field.DurationUnit != 0 {
+		return strconv.FormatInt(int64(*d/field.DurationUnit), 10), true
+	}
+
+	switch v := value.(type) {
+	case *bool:
+		return o.formatBool(*v), true
+	case *string:
+		return *v, true
+	case *float32:
+		return strconv.FormatFloat(float64(*v), 'g', -1, 32), true
+	case *complex64:
+		return strconv.FormatComplex(complex128(*v), 'g', -1, 64), true
+	case *complex128:
+		return strconv.FormatComplex(*v, 'g', -1, 128), true
+	case *time.Time:
+		return v.Format(time.RFC3339), true
+	case timeValue:
+		layout := time.RFC3339
+		if len(v.layouts) > 0 {
+			layout = v.layouts[0]
+		}
+		return formatTimeLayout(*v.value, layout), true
+	case *time.Duration:
+		return v.String(), true
+	case *time.Weekday:
+		return v.String(), true
+	case *time.Month:
+		return v.String(), true
+	case *net.IPNet:
+		return v.String(), true
+	case *url.URL:
+		return v.String(), true
+	case *mail.Address:
+		return v.String(), true
+	case *json.Number:
+		return string(*v), true
+	case *json.RawMessage:
+		return string(*v), true
+	case *big.Int:
+		return v.Text(field.formatBase()), true
+	case *big.Float:
+		return v.Text('g', -1), true
+	case *big.Rat:
+		return v.RatString(), true
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		return string(b), err == nil
+	}
+
+	pv := reflect.ValueOf(value)
+	if pv.Kind() != reflect.Ptr {
+		return "", false
+	}
+	ev := pv.Elem()
+	switch {
+	case ev.Kind() == reflect.Ptr: // pointer to pointer
+		if ev.IsNil() {
+			return "", false
+		}
+		return o.formatValue(field, ev.Interface())
+	case isIntKind(ev.Kind()):
+		return strconv.FormatInt(ev.Int(), field.formatBase()), true
+	case isUintKind(ev.Kind()):
+		return strconv.FormatUint(ev.Uint(), field.formatBase()), true
+	case ev.Kind() == reflect.Float32 || ev.Kind() == reflect.Float64:
+		return strconv.FormatFloat(ev.Float(), 'g', -1, ev.Type().Bits()), true
+	}
+	return "", false
+}
+
+// formatBase returns base used to format integers for f (10 for ScanBaseAuto).
+func (f *ScanField) formatBase() int {
+	if base := f.intBase(); base != 0 {
+		return base
+	}
+	return 10
+}
+
+// formatBool formats b using the first of TrueValues & FalseValues ("on" & "off" by default, "true" & "false" if StrconvBool is set).
+func (o *ScanOptions) formatBool(b bool) string {
+	switch {
+	case o.StrconvBool:
+		return strconv.FormatBool(b)
+	case b && len(o.TrueValues) > 0:
+		return o.TrueValues[0]
+	case b:
+		return scanBoolTrueString
+	case len(o.FalseValues) > 0:
+		return o.FalseValues[0]
+	}
+	return scanBoolFalseString
+}
+
+// formatTimeLayout formats t using given layout (which may be TimeLayoutUnix or TimeLayoutUnixMilli).
+func formatTimeLayout(t time.Time, layout string) string {
+	switch layout {
+	case TimeLayoutUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeLayoutUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return t.Format(layout)
+}
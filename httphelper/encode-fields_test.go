@@ -0,0 +1,201 @@
+package httphelper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeFieldsCheckboxRoundTrip(t *testing.T) {
+	for _, scanner := range []*Scanner{NewScanner(ScanOptions{}), NewScanner(ScanOptions{TrueValues: []string{"yes"}, FalseValues: []string{"no"}})} {
+		for _, checked := range []bool{true, false} {
+			b := checked
+			v := scanner.Encode(ScanField{Name: "agree", Value: &b, Checkbox: true})
+			if _, present := v["agree"]; present != checked {
+				t.Errorf("%v: expected present %v, got %v", checked, checked, v)
+			}
+
+			b = !checked
+			if err := scanner.Scan(newFormRequest(t, v.Encode()), ScanField{Name: "agree", Value: &b, Checkbox: true}); err != nil {
+				t.Errorf("%v: unexpected error %v", checked, err)
+			} else if b != checked {
+				t.Errorf("%v: round trip through %v results in %v", checked, v, b)
+			}
+		}
+	}
+}
+
+func TestEncodeFieldsNilPointer(t *testing.T) {
+	fields := []ScanField{
+		{Name: "s", Value: (*string)(nil)},
+		{Name: "i", Value: (*int)(nil)},
+		{Name: "t", Value: (*time.Time)(nil)},
+		{Name: "ns", Value: (*sql.NullString)(nil)},
+		{Name: "ip", Value: (*net.IP)(nil)},
+		{Name: "b", Value: (*big.Int)(nil)},
+		{Name: "pp", Value: (**int)(nil)},
+		{Name: "sl", Value: (*[]int)(nil)},
+		{Name: "c", Value: (*bool)(nil), Checkbox: true},
+		ScanTimeField{Name: "tf", Format: TimeFormatUnixMillis}.ScanField(),
+	}
+	if v := EncodeFields(fields...); len(v) != 0 {
+		t.Errorf("expected no values, got %v", v)
+	}
+}
+
+// encodeEqual compares variables pointed by a & b (times & big floats are compared by value).
+func encodeEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case *time.Time:
+		return a.Equal(*b.(*time.Time))
+	case *big.Float:
+		return a.Cmp(b.(*big.Float)) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func TestEncodeFieldsRoundTrip(t *testing.T) {
+	var (
+		i       = -42
+		i8      = int8(-8)
+		u64     = uint64(1<<64 - 1)
+		hex     = 255
+		id      = UserID(7)
+		f       = 0.1
+		c       = complex(1, -2)
+		b       = true
+		s       = "hello, world"
+		tm      = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		d       = 90 * time.Second
+		dUnit   = 30 * time.Second
+		wd      = time.Friday
+		m       = time.March
+		ip      = net.ParseIP("2001:db8::1")
+		_, n, _ = net.ParseCIDR("10.0.0.0/8")
+		addr    = netip.MustParseAddr("192.168.1.1")
+		prefix  = netip.MustParsePrefix("192.168.0.0/16")
+		u, _    = url.Parse("https://example.com/a?b=c")
+		email   = mail.Address{Name: "John", Address: "john@example.com"}
+		num     = json.Number("1.5e3")
+		raw     = json.RawMessage(`{"a":[1,2]}`)
+		bi, _   = new(big.Int).SetString("123456789012345678901234567890", 10)
+		bf      = big.NewFloat(1.25)
+		br      = big.NewRat(1, 3)
+		ints    = []int{3, 1, 2}
+		arr     = [3]int{1, 2, 3}
+		tags    = []string{"a", "b"}
+		r       = 'ж'
+		bytes   = []byte{0, 1, 254}
+		uuid    = [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+		ni      = sql.NullInt64{Int64: 5, Valid: true}
+		pi      = &i
+	)
+	fields := []ScanField{
+		{Name: "i", Value: &i},
+		{Name: "i8", Value: &i8},
+		{Name: "u64", Value: &u64},
+		{Name: "hex", Value: &hex, Base: 16},
+		{Name: "id", Value: &id},
+		{Name: "f", Value: &f},
+		{Name: "c", Value: &c},
+		{Name: "b", Value: &b},
+		{Name: "s", Value: &s},
+		{Name: "tm", Value: &tm},
+		{Name: "d", Value: &d},
+		{Name: "dUnit", Value: &dUnit, DurationUnit: time.Second},
+		{Name: "wd", Value: &wd},
+		{Name: "m", Value: &m},
+		{Name: "ip", Value: &ip},
+		{Name: "n", Value: n},
+		{Name: "addr", Value: &addr},
+		{Name: "prefix", Value: &prefix},
+		{Name: "u", Value: u},
+		{Name: "email", Value: &email},
+		{Name: "num", Value: &num},
+		{Name: "raw", Value: &raw},
+		{Name: "bi", Value: bi},
+		{Name: "bf", Value: bf},
+		{Name: "br", Value: br},
+		{Name: "ints", Value: &ints},
+		{Name: "arr", Value: &arr},
+		{Name: "tags", Value: &tags, Split: true},
+		{Name: "r", Value: &r, Char: true},
+		{Name: "bytes", Value: &bytes, Encoding: ScanEncodingBase64URL},
+		{Name: "uuid", Value: &uuid, UUID: true},
+		{Name: "ni", Value: &ni},
+		{Name: "pi", Value: &pi},
+	}
+	for _, field := range fields {
+		v := EncodeFields(field)
+		scanned := field
+		scanned.Value = reflect.New(reflect.TypeOf(field.Value).Elem()).Interface()
+		if err := ScanValues(v, scanned); err != nil {
+			t.Errorf("%s: encoded as %v, scan error %v", field.Name, v, err)
+		} else if !encodeEqual(field.Value, scanned.Value) {
+			t.Errorf("%s: encoded as %v, scanned back as %v", field.Name, v, reflect.ValueOf(scanned.Value).Elem())
+		}
+	}
+}
+
+func TestEncodeFieldsRoundTripAbsent(t *testing.T) {
+	var (
+		pi *int
+		ns sql.NullString
+		no bool
+	)
+	fields := []ScanField{
+		{Name: "pi", Value: &pi},
+		{Name: "ns", Value: &ns},
+		{Name: "no", Value: &no, Checkbox: true},
+	}
+	v := EncodeFields(fields...)
+	if len(v) != 0 {
+		t.Fatalf("expected no values, got %v", v)
+	}
+	one := 1
+	pi, ns, no = &one, sql.NullString{String: "x", Valid: true}, true
+	for i := range fields {
+		fields[i].Optional = true
+	}
+	if err := ScanValues(v, fields...); err != nil {
+		t.Fatal(err)
+	}
+	if pi != nil || ns.Valid || no {
+		t.Errorf("expected nil, invalid & false, got %v, %+v & %v", pi, ns, no)
+	}
+}
+
+func TestEncodeFieldsRoundTripTime(t *testing.T) {
+	times := []time.Time{
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(1600, 5, 6, 7, 8, 9, 0, time.UTC),
+		time.Date(3000, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+	formats := []TimeFormat{TimeFormatRFC3339, TimeFormatDateOnly, TimeFormatUnixSeconds, TimeFormatUnixMillis}
+	for _, format := range formats {
+		for _, tm := range times {
+			if format == TimeFormatDateOnly {
+				tm = tm.Truncate(24 * time.Hour)
+			}
+			if format == TimeFormatUnixMillis {
+				tm = tm.Add(123 * time.Millisecond)
+			}
+			v := EncodeFields(ScanTimeField{Name: "t", Value: &tm, Format: format}.ScanField())
+			var scanned time.Time
+			if err := ScanValues(v, ScanTimeField{Name: "t", Value: &scanned, Format: format}.ScanField()); err != nil {
+				t.Errorf("format %d, %v: encoded as %v, scan error %v", format, tm, v, err)
+			} else if !scanned.Equal(tm) {
+				t.Errorf("format %d, %v: encoded as %v, scanned back as %v", format, tm, v, scanned)
+			}
+		}
+	}
+}
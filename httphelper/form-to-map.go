@@ -0,0 +1,33 @@
+package httphelper
+
+import "net/http"
+
+// FormToMap returns all single-valued fields of Request.Form as map from field name to its value.
+// Fields with more than one value (and with no values) are skipped, use FormToMultiMap to get them.
+// Warning: r.ParseForm should be performed before calling this function.
+func FormToMap(r *http.Request) map[string]string {
+	m := make(map[string]string, len(r.Form))
+	for name, values := range r.Form {
+		if len(values) == 1 {
+			m[name] = values[0]
+		}
+	}
+	return m
+}
+
+// FormToMultiMap returns all fields of Request.Form as map from field name to all of its values.
+// Returned map is a copy, so it can be modified without affecting r.Form.
+// Warning: r.ParseForm should be performed before calling this function.
+func FormToMultiMap(r *http.Request) map[string][]string {
+	m := make(map[string][]string, len(r.Form))
+	for name, values := range r.Form {
+		m[name] = append([]string(nil), values...)
+	}
+	return m
+}
+
+// FormFieldCount returns number of values of field with given name in Request.Form (0 if there is no such field).
+// Warning: r.ParseForm should be performed before calling this function.
+func FormFieldCount(r *http.Request, name string) int {
+	return len(r.Form[name])
+}
@@ -0,0 +1,115 @@
+package httphelper
+
+import (
+	"net/http"
+	"time"
+)
+
+// ScanBuilder accumulates fields for ScanFormData.
+// It allows to describe fields in fluent style:
+//
+//	err := NewScanBuilder().Int("age", &age).String("name", &name).Bool("active", &active).Scan(r)
+type ScanBuilder struct {
+	fields []ScanField
+}
+
+// NewScanBuilder returns new ScanBuilder without fields.
+func NewScanBuilder() *ScanBuilder {
+	return &ScanBuilder{}
+}
+
+// Field adds arbitrary field to b (useful for fields with options, i.e. Optional or Default). It returns b.
+func (b *ScanBuilder) Field(field ScanField) *ScanBuilder {
+	b.fields = append(b.fields, field)
+	return b
+}
+
+// Fields returns all fields accumulated in b.
+func (b *ScanBuilder) Fields() []ScanField {
+	return b.fields
+}
+
+// Scan calls ScanFormData for r with all fields accumulated in b.
+func (b *ScanBuilder) Scan(r *http.Request) error {
+	return ScanFormData(r, b.fields...)
+}
+
+// Int adds int field with given name to b. It returns b.
+func (b *ScanBuilder) Int(name string, value *int) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Int8 adds int8 field with given name to b. It returns b.
+func (b *ScanBuilder) Int8(name string, value *int8) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Int16 adds int16 field with given name to b. It returns b.
+func (b *ScanBuilder) Int16(name string, value *int16) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Int32 adds int32 field with given name to b. It returns b.
+func (b *ScanBuilder) Int32(name string, value *int32) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Int64 adds int64 field with given name to b. It returns b.
+func (b *ScanBuilder) Int64(name string, value *int64) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Uint adds uint field with given name to b. It returns b.
+func (b *ScanBuilder) Uint(name string, value *uint) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Uint8 adds uint8 field with given name to b. It returns b.
+func (b *ScanBuilder) Uint8(name string, value *uint8) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Uint16 adds uint16 field with given name to b. It returns b.
+func (b *ScanBuilder) Uint16(name string, value *uint16) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Uint32 adds uint32 field with given name to b. It returns b.
+func (b *ScanBuilder) Uint32(name string, value *uint32) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Uint64 adds uint64 field with given name to b. It returns b.
+func (b *ScanBuilder) Uint64(name string, value *uint64) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Float32 adds float32 field with given name to b. It returns b.
+func (b *ScanBuilder) Float32(name string, value *float32) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Float64 adds float64 field with given name to b. It returns b.
+func (b *ScanBuilder) Float64(name string, value *float64) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Bool adds bool field with given name to b. It returns b.
+func (b *ScanBuilder) Bool(name string, value *bool) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// String adds string field with given name to b. It returns b.
+func (b *ScanBuilder) String(name string, value *string) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Duration adds time.Duration field with given name to b. It returns b.
+func (b *ScanBuilder) Duration(name string, value *time.Duration) *ScanBuilder {
+	return b.Field(ScanField{Name: name, Value: value})
+}
+
+// Time adds time.Time field with given name and layout (time.RFC3339 if empty) to b. It returns b.
+func (b *ScanBuilder) Time(name string, value *time.Time, layout string) *ScanBuilder {
+	return b.Field(ScanTimeField{Name: name, Value: value, Layout: layout}.ScanField())
+}
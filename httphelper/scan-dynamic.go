@@ -0,0 +1,84 @@
+package httphelper
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FieldDef describes field for ScanDynamic (useful if schema of form is known only at run time).
+type FieldDef struct {
+	Name     string // field name
+	Kind     string // kind of value: "int", "int64", "uint", "uint64", "float", "bool", "string", "time", "duration", "ip", "url" or "email" (prefixed with "[]" for slice of values, i.e. "[]int")
+	Optional bool   // if true then field may be absent in form (in this case result does not contain it)
+	Layout   string // layout for time.Parse for "time" kind (time.RFC3339 if empty), not used for "[]time"
+}
+
+// Types of values for FieldDef kinds.
+var fieldDefTypes = map[string]reflect.Type{
+	"int":      reflect.TypeOf(int(0)),
+	"int64":    reflect.TypeOf(int64(0)),
+	"uint":     reflect.TypeOf(uint(0)),
+	"uint64":   reflect.TypeOf(uint64(0)),
+	"float":    reflect.TypeOf(float64(0)),
+	"bool":     reflect.TypeOf(false),
+	"string":   reflect.TypeOf(""),
+	"time":     reflect.TypeOf(time.Time{}),
+	"duration": reflect.TypeOf(time.Duration(0)),
+	"ip":       reflect.TypeOf(net.IP{}),
+	"url":      reflect.TypeOf(url.URL{}),
+	"email":    reflect.TypeOf(mail.Address{}),
+}
+
+// valueType returns type of value for d.Kind (false if kind is unknown).
+func (d FieldDef) valueType() (reflect.Type, bool) {
+	kind := strings.TrimPrefix(d.Kind, "[]")
+	t, ok := fieldDefTypes[kind]
+	if ok && kind != d.Kind {
+		t = reflect.SliceOf(t)
+	}
+	return t, ok
+}
+
+// ScanDynamic scans Request.Form for fields described by defs and returns map from field name to parsed value (i.e. int for "int" kind, []int for "[]int" kind).
+// It obeys all ScanFormData rules, absent optional fields are not included in result.
+// If kind of field is unknown then ScanError with type ScanErrorTypeIncompatibleType is returned.
+// Returned error is always of type ScanError or nil.
+// Warning: r.ParseForm should be performed before calling this function.
+func ScanDynamic(r *http.Request, defs []FieldDef) (map[string]interface{}, error) {
+	var options ScanOptions
+	result := make(map[string]interface{}, len(defs))
+	for i, def := range defs {
+		t, ok := def.valueType()
+		if !ok {
+			return nil, ScanError{FieldNum: i, FieldName: def.Name, Type: ScanErrorTypeIncompatibleType, SubError: errors.New("unknown kind '" + def.Kind + "'."), ElementIndex: -1}
+		}
+
+		if def.Kind == "time" && def.Layout != "" {
+			var value time.Time
+			field := ScanTimeField{Name: def.Name, Value: &value, Layout: def.Layout}.ScanField()
+			field.Optional = def.Optional
+			if err := options.scanField(r.Form, nil, i, field); err != nil {
+				return nil, err
+			}
+			if options.present(r.Form, nil, &field) {
+				result[def.Name] = value
+			}
+			continue
+		}
+
+		var value interface{}
+		if err := options.scanField(r.Form, nil, i, ScanField{Name: def.Name, Value: &value, Type: t, Optional: def.Optional}); err != nil {
+			return nil, err
+		}
+		if value != nil {
+			result[def.Name] = value
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package httphelper
+
+import (
+	"errors"
+	"net/http"
+)
+
+// StatusCode returns HTTP status code which is appropriate to reply with if scanning fails with error of type t.
+// It is 400 (bad request) for missing or malformed values, 422 (unprocessable entity) for values which fail validation (i.e. out of range) and 500 (internal server error) for unsupported field types.
+func (t ScanErrorType) StatusCode() int {
+	switch t {
+	case ScanErrorTypeIncompatibleType:
+		return http.StatusInternalServerError
+	case ScanErrorTypeOutOfRange, ScanErrorTypeLengthViolation, ScanErrorTypePatternMismatch, ScanErrorTypeNotAllowedValue, ScanErrorTypeValidationError:
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// scanErrorStatusCode returns HTTP status code which is appropriate to reply with if scanning fails with err.
+// For ScanErrors the most severe status code is returned (500 over 400 over 422), for ParseFormError it is 400 (or 413 if body is too large).
+func scanErrorStatusCode(err error) int {
+	var se ScanError
+	var ses ScanErrors
+	var pfe ParseFormError
+	switch {
+	case errors.As(err, &ses):
+		code := 0
+		for _, e := range ses {
+			switch c := e.Type.StatusCode(); {
+			case c == http.StatusInternalServerError:
+				return c
+			case c == http.StatusBadRequest, code == 0:
+				code = c
+			}
+		}
+		if code == 0 {
+			code = http.StatusBadRequest
+		}
+		return code
+	case errors.As(err, &se):
+		return se.Type.StatusCode()
+	case errors.As(err, &pfe):
+		var mbe *http.MaxBytesError
+		if errors.As(pfe.Err, &mbe) {
+			return http.StatusRequestEntityTooLarge
+		}
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteScanError replies to the request with an HTTP error describing err returned by scanning function (i.e. ScanFormData).
+// Status code is chosen by type of error (see ScanErrorType.StatusCode), body is a text representation of err.
+// It does nothing if err is nil.
+func WriteScanError(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	http.Error(w, err.Error(), scanErrorStatusCode(err))
+}
@@ -0,0 +1,361 @@
+package httphelper
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ScanErrorType define the type of error occurred while scanning form
+type ScanErrorType uint8
+
+// Define available ScanError types
+const (
+	ScanErrorTypeNoSuchField        ScanErrorType = iota // There is not field in form with requested name
+	ScanErrorTypeMultipleValues                          // There is more than 1 field in form with requested name (only for non-slice fields, slice fields consume all values)
+	ScanErrorTypeIncompatibleValue                       // Value in form is incompatible with requested field type (i.e. trying to save "one" as int)
+	ScanErrorTypeIncompatibleType                        // Function unable to handle field with such type (i.e. truing to scan custom type)
+	ScanErrorTypeOutOfRange                              // Value in form is parsed successfully but it is out of field's range (see ScanField.Min & ScanField.Max)
+	ScanErrorTypeLengthViolation                         // Length of string value in form violates field's constraints (see ScanField.MinLen & ScanField.MaxLen)
+	ScanErrorTypePatternMismatch                         // String value in form does not match field's pattern (see ScanField.Pattern)
+	ScanErrorTypeNotAllowedValue                         // String value in form is not one of field's allowed values (see ScanField.AllowedValues)
+	ScanErrorTypeOverflow                                // Value in form is a valid number but it does not fit in field's type (i.e. trying to save "300" as int8)
+	ScanErrorTypeUnexpectedField                         // There is field in form which is not requested (only if ScanOptions.StrictUnknown is set)
+	ScanErrorTypeLimitExceeded                           // Value in form is too long or field is repeated too many times (see ScanOptions.MaxValueLen & ScanOptions.MaxFieldRepeats)
+	ScanErrorTypeValidationError                         // Value in form is parsed successfully but field's validator rejects it (see ScanField.Validator)
+	ScanErrorTypeValueCountMismatch                      // Number of values in form does not match length of array field (i.e. 2 values for [3]int)
+	ScanErrorTypeEmptyValue                              // Field in form is present but its value is empty (only for required fields if ScanOptions.EmptyValueError is set)
+)
+
+// String returns stable text representation of t (i.e. "no_such_field" for ScanErrorTypeNoSuchField).
+func (t ScanErrorType) String() string {
+	switch t {
+	case ScanErrorTypeNoSuchField:
+		return "no_such_field"
+	case ScanErrorTypeMultipleValues:
+		return "multiple_values"
+	case ScanErrorTypeIncompatibleValue:
+		return "incompatible_value"
+	case ScanErrorTypeIncompatibleType:
+		return "incompatible_type"
+	case ScanErrorTypeOutOfRange:
+		return "out_of_range"
+	case ScanErrorTypeLengthViolation:
+		return "length_violation"
+	case ScanErrorTypePatternMismatch:
+		return "pattern_mismatch"
+	case ScanErrorTypeNotAllowedValue:
+		return "not_allowed_value"
+	case ScanErrorTypeOverflow:
+		return "overflow"
+	case ScanErrorTypeUnexpectedField:
+		return "unexpected_field"
+	case ScanErrorTypeLimitExceeded:
+		return "limit_exceeded"
+	case ScanErrorTypeValidationError:
+		return "validation_error"
+	case ScanErrorTypeValueCountMismatch:
+		return "value_count_mismatch"
+	case ScanErrorTypeEmptyValue:
+		return "empty_value"
+	}
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler interface for ScanErrorType, so it is marshaled to JSON as string.
+func (t ScanErrorType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Sentinel errors for each ScanErrorType. ScanError matches (using errors.Is) sentinel corresponding to its type.
+var (
+	ErrNoSuchField        = errors.New("no such field")
+	ErrMultipleValues     = errors.New("multiple values")
+	ErrIncompatibleValue  = errors.New("incompatible value")
+	ErrIncompatibleType   = errors.New("incompatible type")
+	ErrOutOfRange         = errors.New("value out of range")
+	ErrLengthViolation    = errors.New("length violation")
+	ErrPatternMismatch    = errors.New("pattern mismatch")
+	ErrNotAllowedValue    = errors.New("not allowed value")
+	ErrOverflow           = errors.New("overflow")
+	ErrUnexpectedField    = errors.New("unexpected field")
+	ErrLimitExceeded      = errors.New("limit exceeded")
+	ErrValidation         = errors.New("validation error")
+	ErrValueCountMismatch = errors.New("value count mismatch")
+	ErrEmptyValue         = errors.New("empty value")
+)
+
+// sentinel returns sentinel error corresponding to t (nil for unknown type).
+func (t ScanErrorType) sentinel() error {
+	switch t {
+	case ScanErrorTypeNoSuchField:
+		return ErrNoSuchField
+	case ScanErrorTypeMultipleValues:
+		return ErrMultipleValues
+	case ScanErrorTypeIncompatibleValue:
+		return ErrIncompatibleValue
+	case ScanErrorTypeIncompatibleType:
+		return ErrIncompatibleType
+	case ScanErrorTypeOutOfRange:
+		return ErrOutOfRange
+	case ScanErrorTypeLengthViolation:
+		return ErrLengthViolation
+	case ScanErrorTypePatternMismatch:
+		return ErrPatternMismatch
+	case ScanErrorTypeNotAllowedValue:
+		return ErrNotAllowedValue
+	case ScanErrorTypeOverflow:
+		return ErrOverflow
+	case ScanErrorTypeUnexpectedField:
+		return ErrUnexpectedField
+	case ScanErrorTypeLimitExceeded:
+		return ErrLimitExceeded
+	case ScanErrorTypeValidationError:
+		return ErrValidation
+	case ScanErrorTypeValueCountMismatch:
+		return ErrValueCountMismatch
+	case ScanErrorTypeEmptyValue:
+		return ErrEmptyValue
+	}
+	return nil
+}
+
+// ScanError define error occurred while scanning form
+// ScanError may be marshaled to JSON, in this case Type is represented as string (see ScanErrorType.String) and SubError - as its message.
+type ScanError struct {
+	FieldNum     int           `json:"field_num"`            // problem field number (beginning from 0), -1 for ScanErrorTypeUnexpectedField
+	FieldName    string        `json:"field_name"`           // problem field name
+	Type         ScanErrorType `json:"type"`                 // type of error
+	SubError     error         `json:"sub_error,omitempty"`  // child error, used to exactly describe problem with incompatible value or type (nil for other types of error)
+	Value        string        `json:"value,omitempty"`      // raw form value which causes error (empty for ScanErrorTypeNoSuchField, all values joined with ", " for ScanErrorTypeMultipleValues)
+	ElementIndex int           `json:"element_index"`        // index of problem element for slice & array fields (after splitting, if any), -1 if error is not related to single element
+	FieldPath    string        `json:"field_path,omitempty"` // path of problem struct field (i.e. "Address.Zip"), set only by BindForm & DecodeForm
+	Label        string        `json:"label,omitempty"`      // human-readable name of problem field (see ScanField.Label)
+	Message      string        `json:"-"`                    // template of user-facing message (see ScanField.Message)
+}
+
+// scanErrorJSON is used to marshal ScanError to JSON without recursion.
+type scanErrorJSON ScanError
+
+// MarshalJSON implements json.Marshaler interface for ScanError. SubError is represented as its message.
+func (e ScanError) MarshalJSON() ([]byte, error) {
+	var subError string
+	if e.SubError != nil {
+		subError = e.SubError.Error()
+	}
+	return json.Marshal(struct {
+		scanErrorJSON
+		SubError string `json:"sub_error,omitempty"`
+	}{scanErrorJSON(e), subError})
+}
+
+func scanErrorNoSuchField(fieldNum int, fieldName string) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeNoSuchField, SubError: nil, ElementIndex: -1}
+}
+
+func scanErrorMultipleValues(fieldNum int, fieldName string, values []string) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeMultipleValues, SubError: nil, Value: strings.Join(values, ", "), ElementIndex: -1}
+}
+
+// scanErrorIncompatibleValue returns error with type ScanErrorTypeIncompatibleValue or ScanErrorTypeOverflow (if subError is range error from strconv).
+func scanErrorIncompatibleValue(fieldNum int, fieldName string, value string, subError error) ScanError {
+	if errors.Is(subError, strconv.ErrRange) {
+		return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeOverflow, SubError: subError, Value: value, ElementIndex: -1}
+	}
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeIncompatibleValue, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorIncompatibleType(fieldNum int, fieldName string, value string) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeIncompatibleType, SubError: nil, Value: value, ElementIndex: -1}
+}
+
+func scanErrorOutOfRange(fieldNum int, fieldName string, value string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeOutOfRange, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorLengthViolation(fieldNum int, fieldName string, value string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeLengthViolation, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorPatternMismatch(fieldNum int, fieldName string, value string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypePatternMismatch, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorNotAllowedValue(fieldNum int, fieldName string, value string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeNotAllowedValue, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorUnexpectedField(fieldName string, values []string) ScanError {
+	return ScanError{FieldNum: -1, FieldName: fieldName, Type: ScanErrorTypeUnexpectedField, SubError: nil, Value: strings.Join(values, ", "), ElementIndex: -1}
+}
+
+func scanErrorLimitExceeded(fieldNum int, fieldName string, value string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeLimitExceeded, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorValidation(fieldNum int, fieldName string, value string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeValidationError, SubError: subError, Value: value, ElementIndex: -1}
+}
+
+func scanErrorValueCountMismatch(fieldNum int, fieldName string, values []string, subError error) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeValueCountMismatch, SubError: subError, Value: strings.Join(values, ", "), ElementIndex: -1}
+}
+
+func scanErrorEmptyValue(fieldNum int, fieldName string) ScanError {
+	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeEmptyValue, SubError: nil, ElementIndex: -1}
+}
+
+// Error Implement error interface for ScanError. It returns text representation of error.
+func (e ScanError) Error() string {
+	prefix := "Scan error in #" + strconv.Itoa(e.FieldNum) + " field with name '" + e.FieldName + "': "
+	if e.FieldNum < 0 {
+		prefix = "Scan error in field with name '" + e.FieldName + "': "
+	}
+	if e.FieldPath != "" {
+		prefix = prefix[:len(prefix)-2] + " (struct field '" + e.FieldPath + "'): "
+	}
+	return prefix + e.description()
+}
+
+// UserMessage returns human-readable message suitable to show to user (i.e. "Please enter a valid age").
+// If Message is set then it is used as template: "{label}" is replaced with Label (or FieldName if Label is empty) and "{value}" - with Value.
+// Otherwise if Label is set then message is Label followed by description of error (i.e. "Age: value is out of range.").
+// Otherwise it returns the same as Error.
+func (e ScanError) UserMessage() string {
+	label := e.Label
+	if label == "" {
+		label = e.FieldName
+	}
+	switch {
+	case e.Message != "":
+		return strings.NewReplacer("{label}", label, "{value}", e.Value).Replace(e.Message)
+	case e.Label != "":
+		return e.Label + ": " + e.description()
+	}
+	return e.Error()
+}
+
+// description returns text description of error without field information.
+func (e ScanError) description() string {
+	switch e.Type {
+	case ScanErrorTypeNoSuchField:
+		return "no field with such name."
+	case ScanErrorTypeMultipleValues:
+		return "there is more than 1 field with such name."
+	case ScanErrorTypeIncompatibleValue:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "unable to parse string to required type."
+	case ScanErrorTypeIncompatibleType:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return " type of this field is imcompatible with this function type."
+	case ScanErrorTypeOutOfRange:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "value is out of range."
+	case ScanErrorTypeLengthViolation:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "length of value is out of range."
+	case ScanErrorTypePatternMismatch:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "value does not match pattern."
+	case ScanErrorTypeNotAllowedValue:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "value is not allowed."
+	case ScanErrorTypeOverflow:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "value is out of range of field type."
+	case ScanErrorTypeUnexpectedField:
+		return "field is not expected."
+	case ScanErrorTypeLimitExceeded:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "limit exceeded."
+	case ScanErrorTypeValidationError:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "value is invalid."
+	case ScanErrorTypeValueCountMismatch:
+		if e.SubError != nil {
+			return e.SubError.Error()
+		}
+		return "number of values does not match length of field."
+	case ScanErrorTypeEmptyValue:
+		return "value is empty."
+	}
+	return "unknown error"
+}
+
+// Unwrap returns SubError, so errors.Is & errors.As can examine child error (i.e. errors.Is(err, strconv.ErrSyntax)).
+func (e ScanError) Unwrap() error {
+	return e.SubError
+}
+
+// Is reports whether target is a sentinel error corresponding to e.Type (i.e. ErrNoSuchField for ScanErrorTypeNoSuchField).
+func (e ScanError) Is(target error) bool {
+	return target == e.Type.sentinel()
+}
+
+// ScanErrors is a list of errors occurred while scanning form (see ScanFormDataAll).
+type ScanErrors []ScanError
+
+// Error implements error interface for ScanErrors. It returns text representations of all errors separated by "; ".
+func (e ScanErrors) Error() string {
+	s := make([]string, len(e))
+	for i := range e {
+		s[i] = e[i].Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Failed reports whether e contains error for field with given number (so this field has not been scanned).
+// It is useful with ScanFormDataAll & ScanOptions.ContinueOnError to find out which fields have been scanned successfully.
+func (e ScanErrors) Failed(fieldNum int) bool {
+	for i := range e {
+		if e[i].FieldNum == fieldNum {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns all errors as []error, so errors.Is & errors.As can examine each of them.
+func (e ScanErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+	return errs
+}
+
+// ParseFormError is returned if Request.ParseForm automatically called by scanning function (see ScanOptions.AutoParseForm) fails.
+// If body exceeds ScanOptions.MaxFormBytes then Err wraps *http.MaxBytesError (use errors.As to check it).
+// It allows to distinguish malformed request from missing fields.
+type ParseFormError struct {
+	Err error // error returned by Request.ParseForm
+}
+
+// Error implements error interface for ParseFormError.
+func (e ParseFormError) Error() string {
+	return "Unable to parse form: " + e.Err.Error()
+}
+
+// Unwrap returns error returned by Request.ParseForm.
+func (e ParseFormError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,123 @@
+package httphelper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScanErrorIncompatibleValueMessage(t *testing.T) {
+	var i int
+	err := ScanValues(url.Values{"age": {"abc"}}, ScanField{Name: "age", Value: &i})
+	var se ScanError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected ScanError, got %#v", err)
+	}
+	if se.Type != ScanErrorTypeIncompatibleValue {
+		t.Errorf("expected type %v, got %v", ScanErrorTypeIncompatibleValue, se.Type)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected strconv.ErrSyntax in chain of %v", err)
+	}
+	msg := se.Error()
+	if !strings.Contains(msg, se.SubError.Error()) || !strings.Contains(msg, "invalid syntax") {
+		t.Errorf("expected message to contain sub error %q, got %q", se.SubError, msg)
+	}
+}
+
+func TestScanErrorIncompatibleType(t *testing.T) {
+	var s struct{}
+	err := ScanValues(url.Values{"s": {"x"}}, ScanField{Name: "s", Value: &s})
+	var se ScanError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected ScanError, got %#v", err)
+	}
+	if se.Type != ScanErrorTypeIncompatibleType {
+		t.Errorf("expected type %v, got %v", ScanErrorTypeIncompatibleType, se.Type)
+	}
+	if !errors.Is(err, ErrIncompatibleType) {
+		t.Errorf("expected %v to match ErrIncompatibleType", err)
+	}
+}
+
+func TestScanErrorAs(t *testing.T) {
+	var i, j int
+	r := newFormRequest(t, "a=x&b=1")
+	fields := []ScanField{{Name: "a", Value: &i}, {Name: "b", Value: &j}, {Name: "c", Value: &j}}
+
+	var se ScanError
+	if err := ScanFormData(r, fields...); !errors.As(err, &se) || se.FieldName != "a" {
+		t.Errorf("expected ScanError for field 'a' from ScanFormData, got %#v", err)
+	}
+
+	err := ScanFormDataAll(r, fields...)
+	if _, ok := err.(ScanErrors); !ok {
+		t.Fatalf("expected ScanErrors, got %#v", err)
+	}
+	se = ScanError{}
+	if !errors.As(err, &se) || se.FieldName != "a" {
+		t.Errorf("expected first ScanError for field 'a' from ScanErrors, got %#v", se)
+	}
+	if !errors.Is(err, ErrIncompatibleValue) || !errors.Is(err, ErrNoSuchField) {
+		t.Errorf("expected %v to match both ErrIncompatibleValue & ErrNoSuchField", err)
+	}
+	if errors.Is(err, ErrOverflow) {
+		t.Errorf("unexpected match of ErrOverflow for %v", err)
+	}
+}
+
+func TestScanErrorIsSentinel(t *testing.T) {
+	sentinels := []error{
+		ErrNoSuchField, ErrMultipleValues, ErrIncompatibleValue, ErrIncompatibleType, ErrOutOfRange, ErrLengthViolation, ErrPatternMismatch,
+		ErrNotAllowedValue, ErrOverflow, ErrUnexpectedField, ErrLimitExceeded, ErrValidation, ErrValueCountMismatch, ErrEmptyValue,
+	}
+	for i := range sentinels {
+		var err error = ScanError{Type: ScanErrorType(i), ElementIndex: -1}
+		for j, other := range sentinels {
+			if errors.Is(err, other) != (i == j) {
+				t.Errorf("%v: errors.Is(err, %q) = %v", ScanErrorType(i), other, i != j)
+			}
+		}
+	}
+}
+
+func TestScanErrorTypeString(t *testing.T) {
+	if s := ScanErrorTypeOverflow.String(); s != "overflow" {
+		t.Errorf("expected 'overflow', got %q", s)
+	}
+	if s := fmt.Sprint(ScanErrorTypeEmptyValue); s != "empty_value" {
+		t.Errorf("expected 'empty_value', got %q", s)
+	}
+}
+
+func TestScanErrorsFailed(t *testing.T) {
+	a, b, c, d := 1, int8(1), "old", []int{1}
+	var e string
+	fields := []ScanField{
+		{Name: "a", Value: &a, Max: 10},        // constraint error
+		{Name: "b", Value: &b},                 // overflow
+		{Name: "c", Value: &c, MinLen: 5},      // constraint error
+		{Name: "d", Value: &d},                 // parse error of second element
+		{Name: "e", Value: &e},                 // success
+		{Name: "f", Value: &e, Optional: true}, // absent optional
+	}
+	err := ScanFormDataAll(newFormRequest(t, "a=100&b=300&c=new&d=2&d=x&e=ok"), fields...)
+	errs, ok := err.(ScanErrors)
+	if !ok || len(errs) != 4 {
+		t.Fatalf("expected 4 ScanErrors, got %#v", err)
+	}
+	for i := range fields {
+		if failed := errs.Failed(i); failed != (i < 4) {
+			t.Errorf("field #%d: expected failed %v, got %v", i, i < 4, failed)
+		}
+	}
+	if a != 1 || b != 1 || c != "old" || len(d) != 1 || d[0] != 1 {
+		t.Errorf("variables of failed fields are modified: %v, %v, %q, %v", a, b, c, d)
+	}
+	if e != "ok" {
+		t.Errorf("expected 'ok', got %q", e)
+	}
+}
@@ -0,0 +1,1412 @@
+package httphelper
+
+import (
+	"context"
+	"database/sql"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/apaxa-io/strconvhelper"
+	"math/big"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ScanValues scans values for required fields and save its value.
+// It does the same as ScanFormData but for arbitrary url.Values (i.e. already parsed query string or any map[string][]string), so it is not tied to *http.Request.
+// File fields (**multipart.FileHeader) are always absent for ScanValues.
+// Returned error is always of type ScanError or nil.
+func ScanValues(v url.Values, fields ...ScanField) error {
+	var options ScanOptions
+	return options.scanForm(v, nil, fields)
+}
+
+// ScanPathValues does the same as ScanValues but for single-valued map (i.e. path parameters extracted by router for "/users/{id}").
+// Returned error is always of type ScanError or nil.
+func ScanPathValues(vals map[string]string, fields ...ScanField) error {
+	v := make(url.Values, len(vals))
+	for name, value := range vals {
+		v[name] = []string{value}
+	}
+	var options ScanOptions
+	return options.scanForm(v, nil, fields)
+}
+
+// ScanField stores requested field name and variable to save value for ScanFormData.
+type ScanField struct {
+	Name     string         // field name
+	Value    interface{}    // variable to store value
+	Optional bool           // if true and there is no field with such name in form then Value leaves untouched (instead of error)
+	Default  interface{}    // if not nil and there is no field with such name in form then Default assigns to Value (type of Default should be the same as type pointed by Value)
+	Base     int            // base for parsing integers (from 2 to 36), 0 means 10, ScanBaseAuto means base is determined by prefix ("0x", "0b", "0o", "0")
+	Char     bool           // if true then rune (int32) & byte (uint8) fields are scanned as single character instead of number
+	UUID     bool           // if true then [16]byte field (or field of named type with such underlying type, i.e. uuid.UUID) is scanned from single canonical UUID value (i.e. "123e4567-e89b-12d3-a456-426614174000")
+	Checkbox bool           // if true then bool field is true if there is any non-empty value with such name in form and false otherwise (as HTML checkbox with custom value), values themselves are not parsed
+	Encoding ScanEncoding   // if not ScanEncodingNone then []byte field (or encoding.BinaryUnmarshaler) is scanned from single value decoded using this encoding
+	Min      interface{}    // if not nil then minimal allowed value for numeric field (may be of any integer or float type)
+	Max      interface{}    // if not nil then maximal allowed value for numeric field (may be of any integer or float type)
+	MinLen   int            // if not 0 then minimal allowed length (in runes) of value for string field
+	MaxLen   int            // if not 0 then maximal allowed length (in runes) of value for string field
+	Pattern  *regexp.Regexp // if not nil then value for string field should match it
+
+	AllowedValues []string // if not empty then value for string field should be one of them
+
+	Split     bool   // if true then each value for slice field is split by Separator and each part is parsed as separate element (i.e. "red,green,blue")
+	Separator string // separator used if Split is set ("," if empty)
+
+	SplitSpace bool // if true then each value for slice field is split by white space and each part is parsed as separate element (i.e. "1 2 3")
+	Ranges     bool // if true then each value (or part) for integer slice field may be an inclusive range "from-to" which is expanded to all numbers in it (i.e. "10-20")
+
+	Transform func(string) string // if not nil then it is applied to each value before parsing (i.e. to normalize phone number), so validation is performed on transformed value
+
+	Validator func(interface{}) error // if not nil then it is called with parsed value (i.e. int for *int field, each element for slice field) after other constraints are checked, its error is reported as ScanError with type ScanErrorTypeValidationError
+
+	DurationUnit time.Duration // if not 0 then time.Duration field is scanned from integer number of DurationUnit (i.e. "30" with time.Second is 30s) instead of time.ParseDuration format
+
+	Type reflect.Type // if not nil then Value should be *interface{}: value is parsed into new variable of Type and stored to interface pointed by Value (useful if schema of form is known only at run time)
+
+	Label   string // human-readable name of field (i.e. "Age"), it is copied to ScanError and used by ScanError.UserMessage
+	Message string // template of user-facing error message (i.e. "Please enter a valid {label}"), it is copied to ScanError and used by ScanError.UserMessage
+
+	Aliases []string // alternative names of field in form (values with all matched names are combined, so for single value field only one of them may be present in form)
+}
+
+// ScanEncoding define encoding used to decode binary data from form value.
+type ScanEncoding uint8
+
+// Define available ScanEncoding values
+const (
+	ScanEncodingNone         ScanEncoding = iota // No decoding ([]byte field is treated as a usual slice of numbers)
+	ScanEncodingHex                              // Hex encoding (encoding/hex)
+	ScanEncodingBase64                           // Standard base64 encoding (base64.StdEncoding)
+	ScanEncodingBase64URL                        // URL-safe base64 encoding (base64.URLEncoding)
+	ScanEncodingBase64Raw                        // Standard base64 encoding without padding (base64.RawStdEncoding)
+	ScanEncodingBase64RawURL                     // URL-safe base64 encoding without padding (base64.RawURLEncoding)
+)
+
+// encode encodes b using encoding e (b is returned as-is for ScanEncodingNone & unknown encodings).
+func (e ScanEncoding) encode(b []byte) string {
+	switch e {
+	case ScanEncodingHex:
+		return hex.EncodeToString(b)
+	case ScanEncodingBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case ScanEncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(b)
+	case ScanEncodingBase64Raw:
+		return base64.RawStdEncoding.EncodeToString(b)
+	case ScanEncodingBase64RawURL:
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	return string(b)
+}
+
+// decode decodes s using encoding e.
+func (e ScanEncoding) decode(s string) ([]byte, error) {
+	switch e {
+	case ScanEncodingHex:
+		return hex.DecodeString(s)
+	case ScanEncodingBase64:
+		return base64.StdEncoding.DecodeString(s)
+	case ScanEncodingBase64URL:
+		return base64.URLEncoding.DecodeString(s)
+	case ScanEncodingBase64Raw:
+		return base64.RawStdEncoding.DecodeString(s)
+	case ScanEncodingBase64RawURL:
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return nil, errors.New("unknown encoding.")
+}
+
+// ScanBaseAuto is a special value for ScanField.Base. It means base is determined by Go-style prefix of value (as for strconv.ParseInt with base 0).
+const ScanBaseAuto = -1
+
+// intBase returns base which should be passed to strconv.ParseInt & strconv.ParseUint for f.
+func (f *ScanField) intBase() int {
+	switch f.Base {
+	case 0:
+		return 10
+	case ScanBaseAuto:
+		return 0
+	}
+	return f.Base
+}
+
+// scanChar parses s as single character and stores it to variable pointed by value.
+// For rune s should contain exactly one valid UTF-8 encoded code point, for byte s should contain exactly one byte.
+// It returns false if value is not a pointer to rune or byte.
+func scanChar(s string, value interface{}) (ok bool, err error) {
+	switch v := value.(type) {
+	case *rune:
+		r, size := utf8.DecodeRuneInString(s)
+		if (r == utf8.RuneError && size <= 1) || size != len(s) {
+			return true, errors.New("'" + s + "' is not a single character.")
+		}
+		*v = r
+	case *byte:
+		if len(s) != 1 {
+			return true, errors.New("'" + s + "' is not a single byte.")
+		}
+		*v = s[0]
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// scanIntPortable parses integer s with given base as 32-bit integer and stores result to variable pointed by value.
+// It returns false if value is not a pointer to int or uint.
+func scanIntPortable(s string, base int, value interface{}) (ok bool, err error) {
+	switch v := value.(type) {
+	case *int:
+		var i int64
+		i, err = strconv.ParseInt(s, base, 32)
+		*v = int(i)
+	case *uint:
+		var u uint64
+		u, err = strconv.ParseUint(s, base, 32)
+		*v = uint(u)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// scanIntBase parses integer s with given base and stores result to variable pointed by value.
+// It returns false if value is not a pointer to [u]int[8/16/32/64].
+func scanIntBase(s string, base int, value interface{}) (ok bool, err error) {
+	var i int64
+	var u uint64
+	switch v := value.(type) {
+	case *int:
+		i, err = strconv.ParseInt(s, base, strconv.IntSize)
+		*v = int(i)
+	case *int8:
+		i, err = strconv.ParseInt(s, base, 8)
+		*v = int8(i)
+	case *int16:
+		i, err = strconv.ParseInt(s, base, 16)
+		*v = int16(i)
+	case *int32:
+		i, err = strconv.ParseInt(s, base, 32)
+		*v = int32(i)
+	case *int64:
+		*v, err = strconv.ParseInt(s, base, 64)
+	case *uint:
+		u, err = strconv.ParseUint(s, base, strconv.IntSize)
+		*v = uint(u)
+	case *uint8:
+		u, err = strconv.ParseUint(s, base, 8)
+		*v = uint8(u)
+	case *uint16:
+		u, err = strconv.ParseUint(s, base, 16)
+		*v = uint16(u)
+	case *uint32:
+		u, err = strconv.ParseUint(s, base, 32)
+		*v = uint32(u)
+	case *uint64:
+		*v, err = strconv.ParseUint(s, base, 64)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// uuidTarget checks if value is a pointer to [16]byte (or to named type with such underlying type) and returns reflect.Value of pointed array if so.
+func uuidTarget(value interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Array || v.Elem().Len() != 16 || v.Elem().Type().Elem().Kind() != reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+	return v.Elem(), true
+}
+
+// parseUUID parses s as UUID in canonical form (36 characters: 32 hex digits and 4 hyphens, i.e. "123e4567-e89b-12d3-a456-426614174000").
+func parseUUID(s string) (u [16]byte, err error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, errors.New("'" + s + "' is not a valid UUID (should be in form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx).")
+	}
+	if _, err = hex.Decode(u[:], []byte(s[0:8]+s[9:13]+s[14:18]+s[19:23]+s[24:])); err != nil {
+		return u, errors.New("'" + s + "' is not a valid UUID: " + err.Error() + ".")
+	}
+	return u, nil
+}
+
+// formatUUID formats u in canonical form.
+func formatUUID(u [16]byte) string {
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// parseDurationUnit parses s as integer number of units and returns corresponding duration.
+func parseDurationUnit(s string, unit time.Duration) (time.Duration, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(n) * unit
+	if d/unit != time.Duration(n) {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrRange}
+	}
+	return d, nil
+}
+
+// numberTarget checks if value is a pointer to variable of integer or float kind (which is not parsed by itself, i.e. does not implement encoding.TextUnmarshaler) or to big.Int, big.Float or big.Rat.
+// time.Duration, time.Weekday & time.Month are not numbers in this sense as they have their own text formats (i.e. "1.5s").
+func numberTarget(value interface{}) bool {
+	switch value.(type) {
+	case *big.Int, *big.Float, *big.Rat:
+		return true
+	case encoding.TextUnmarshaler, *time.Duration, *time.Weekday, *time.Month:
+		return false
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && !v.IsNil() && isNumberKind(v.Elem().Kind())
+}
+
+// normalizeNumber removes ThousandsSeparator from s and replaces DecimalMark with '.'.
+func (o *ScanOptions) normalizeNumber(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case o.ThousandsSeparator != 0 && r == o.ThousandsSeparator:
+			return -1
+		case o.DecimalMark != 0 && r == o.DecimalMark:
+			return '.'
+		}
+		return r
+	}, s)
+}
+
+// unsignedTarget checks if value is a pointer to variable of unsigned integer kind (which is not parsed by itself, i.e. does not implement encoding.TextUnmarshaler).
+func unsignedTarget(value interface{}) bool {
+	if _, ok := value.(encoding.TextUnmarshaler); ok {
+		return false
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && !v.IsNil() && isUintKind(v.Elem().Kind())
+}
+
+// scanNumberKind parses s as number of kind of variable pointed by value (using reflection) and stores the result.
+// It is used for types which are not matched by type switch (i.e. named types like "type UserID int64" & uintptr).
+// It returns false if value is not a pointer to variable of integer or float kind.
+func (o *ScanOptions) scanNumberKind(s string, base int, value interface{}) (ok bool, err error) {
+	pv := reflect.ValueOf(value)
+	if pv.Kind() != reflect.Ptr || pv.IsNil() {
+		return false, nil
+	}
+	v := pv.Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bitSize := v.Type().Bits()
+		if v.Kind() == reflect.Int && o.PortableIntSize {
+			bitSize = 32
+		}
+		var i int64
+		if i, err = strconv.ParseInt(s, base, bitSize); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bitSize := v.Type().Bits()
+		if v.Kind() == reflect.Uint && o.PortableIntSize {
+			bitSize = 32
+		}
+		var u uint64
+		if u, err = strconv.ParseUint(s, base, bitSize); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// setDefault assigns def to variable pointed by value.
+// It returns false if value is not a pointer or type of def is not the same as type pointed by value.
+func setDefault(value interface{}, def interface{}) bool {
+	if tv, ok := value.(timeValue); ok {
+		value = tv.value
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	d := reflect.ValueOf(def)
+	if d.Type() != v.Elem().Type() {
+		return false
+	}
+	v.Elem().Set(d)
+	return true
+}
+
+// setNil sets variable pointed by value to nil if this variable is a pointer itself (or to invalid value if this variable is of one of sql.Null* types).
+func setNil(value interface{}) {
+	if _, _, isNull := nullValue(value); isNull {
+		v := reflect.ValueOf(value)
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+		return
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Ptr {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+}
+
+// nullValue returns pointers to inner value and to Valid flag if value is a pointer to one of sql.Null* types.
+func nullValue(value interface{}) (inner interface{}, valid *bool, ok bool) {
+	switch v := value.(type) {
+	case *sql.NullString:
+		return &v.String, &v.Valid, true
+	case *sql.NullInt64:
+		return &v.Int64, &v.Valid, true
+	case *sql.NullInt32:
+		return &v.Int32, &v.Valid, true
+	case *sql.NullInt16:
+		return &v.Int16, &v.Valid, true
+	case *sql.NullByte:
+		return &v.Byte, &v.Valid, true
+	case *sql.NullFloat64:
+		return &v.Float64, &v.Valid, true
+	case *sql.NullBool:
+		return &v.Bool, &v.Valid, true
+	case *sql.NullTime:
+		return &v.Time, &v.Valid, true
+	}
+	return nil, nil, false
+}
+
+// FormScanner is an interface which may be implemented by custom types to be scanned by ScanFormData.
+// ScanForm receives raw form value and should parse it into receiver.
+// Error returned by ScanForm is reported as SubError of ScanError with type ScanErrorTypeIncompatibleValue.
+type FormScanner interface {
+	ScanForm(string) error
+}
+
+// ScanTimeField stores requested field name, variable to save time value and layouts used to parse it.
+// Use ScanField method to pass it to ScanFormData.
+type ScanTimeField struct {
+	Name    string     // field name
+	Value   *time.Time // variable to store value
+	Format  TimeFormat // predefined format tried first (if it is not TimeFormatNone), use it instead of Layout for common formats
+	Layout  string     // layout for time.Parse (time.RFC3339 if empty and Format & Layouts are empty too)
+	Layouts []string   // additional layouts tried in order after Layout (if it is not empty), the first successfully parsed is used
+}
+
+// TimeLayoutUnix is a special layout for ScanTimeField. It means value is an integer number of seconds since UNIX epoch.
+const TimeLayoutUnix = "unix"
+
+// TimeLayoutUnixMilli is a special layout for ScanTimeField. It means value is an integer number of milliseconds since UNIX epoch.
+const TimeLayoutUnixMilli = "unixmilli"
+
+// TimeFormat define predefined time format for ScanTimeField.
+type TimeFormat uint8
+
+// Define available TimeFormat values
+const (
+	TimeFormatNone        TimeFormat = iota // No predefined format (Layout & Layouts are used)
+	TimeFormatRFC3339                       // Date and time with time zone (time.RFC3339, i.e. "2006-01-02T15:04:05Z07:00")
+	TimeFormatDateOnly                      // Date only (i.e. "2006-01-02"), time is midnight UTC
+	TimeFormatTimeOnly                      // Time only (i.e. "15:04:05"), date is January 1, year 0
+	TimeFormatUnixSeconds                   // Integer number of seconds since UNIX epoch (TimeLayoutUnix)
+	TimeFormatUnixMillis                    // Integer number of milliseconds since UNIX epoch (TimeLayoutUnixMilli)
+)
+
+// layout returns layout corresponding to f (empty for TimeFormatNone & unknown formats).
+func (f TimeFormat) layout() string {
+	switch f {
+	case TimeFormatRFC3339:
+		return time.RFC3339
+	case TimeFormatDateOnly:
+		return "2006-01-02"
+	case TimeFormatTimeOnly:
+		return "15:04:05"
+	case TimeFormatUnixSeconds:
+		return TimeLayoutUnix
+	case TimeFormatUnixMillis:
+		return TimeLayoutUnixMilli
+	}
+	return ""
+}
+
+// ScanField returns ScanField which can be passed to ScanFormData to scan time value using f.Format, f.Layout & f.Layouts.
+func (f ScanTimeField) ScanField() ScanField {
+	var layouts []string
+	if layout := f.Format.layout(); layout != "" {
+		layouts = append(layouts, layout)
+	}
+	if f.Layout != "" {
+		layouts = append(layouts, f.Layout)
+	}
+	layouts = append(layouts, f.Layouts...)
+	return ScanField{Name: f.Name, Value: timeValue{value: f.Value, layouts: layouts}}
+}
+
+// timeValue is a ScanField.Value for time with custom layouts.
+type timeValue struct {
+	value   *time.Time
+	layouts []string
+}
+
+// parseTime parses s as time trying given layouts in order (time.RFC3339 if there is no layouts).
+// Empty s is always invalid (instead of silently returning zero time).
+func parseTime(s string, layouts ...string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("empty string is not a valid time value.")
+	}
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = parseTimeLayout(s, layout); err == nil {
+			return t, nil
+		}
+	}
+	if len(layouts) > 1 {
+		return time.Time{}, errors.New("'" + s + "' does not match any of time layouts: '" + strings.Join(layouts, "', '") + "'.")
+	}
+	return time.Time{}, err
+}
+
+// parseTimeLayout parses s as time using given layout (which may be TimeLayoutUnix or TimeLayoutUnixMilli).
+func parseTimeLayout(s string, layout string) (time.Time, error) {
+	switch layout {
+	case TimeLayoutUnix:
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(sec, 0), nil
+	case TimeLayoutUnixMilli:
+		msec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(msec/1000, msec%1000*int64(time.Millisecond)), nil
+	}
+	return time.Parse(layout, s)
+}
+
+// parseCalendarName parses s as number from min to max or as English name (case insensitive) of one of them (name returns name for number).
+// It is used for time.Weekday & time.Month.
+func parseCalendarName(s string, min, max int, name func(int) string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < min || n > max {
+			return 0, fmt.Errorf("'%s' is out of range from %d to %d.", s, min, max)
+		}
+		return n, nil
+	}
+	for i := min; i <= max; i++ {
+		if strings.EqualFold(s, name(i)) {
+			return i, nil
+		}
+	}
+	return 0, errors.New("'" + s + "' is not a valid name or number.")
+}
+
+// FormSource define which values of request are scanned and in which order.
+type FormSource uint8
+
+// Define available FormSource values
+const (
+	FormSourceMerged     FormSource = iota // Request.Form (URL query parameters & body values merged by Request.ParseForm)
+	FormSourceQueryFirst                   // URL query parameters, body values (Request.PostForm) are used only for names absent in query
+	FormSourceBodyFirst                    // Body values (Request.PostForm), URL query parameters are used only for names absent in body
+)
+
+const scanBoolTrueString = "on"
+
+const scanBoolFalseString = "off"
+
+// ScanOptions define options for scanning form.
+// Zero value of ScanOptions means default options (which are used by ScanFormData).
+type ScanOptions struct {
+	TrueValues  []string // strings treated as true for bool fields (if empty then "on" is used)
+	FalseValues []string // strings treated as false for bool fields (if empty then "off" is used)
+
+	CaseInsensitiveBool bool // if true then bool values compared to TrueValues & FalseValues case insensitively
+	AbsentBoolIsFalse   bool // if true then absent bool field treated as false (as it happens with unchecked HTML checkbox) instead of error
+	StrconvBool         bool // if true then bool values are parsed using strconv.ParseBool ("1", "t", "true", "0", "f", "false" & etc.) instead of TrueValues & FalseValues
+
+	FirstValueWins bool // if true then the first value is used (and others are ignored) if there are multiple values for non-slice field (instead of error with type ScanErrorTypeMultipleValues)
+	LastValueWins  bool // if true then the last value is used (and others are ignored) if there are multiple values for non-slice field (FirstValueWins has priority)
+
+	CaseInsensitiveNames bool // if true then field names (and aliases) are matched to names in form case insensitively
+
+	TreatEmptyAsAbsent bool // if true then empty values are ignored, so field with only empty values is treated as absent (Optional, Default & etc. are applied)
+	EmptyValueError    bool // if true then empty value of required field (not Optional and without Default) is reported as error with type ScanErrorTypeEmptyValue instead of being parsed
+
+	StrictUnknown bool // if true then error with type ScanErrorTypeUnexpectedField is returned if form contains field which is not requested (after scanning all requested fields)
+
+	Source FormSource // source of form values for functions scanning *http.Request (Request.Form by default)
+
+	AutoParseForm bool  // if true then Request.ParseForm is called if it has not been called yet (Request.Form is nil)
+	MaxFormBytes  int64 // if not 0 and AutoParseForm is set then request body is limited to MaxFormBytes bytes (using http.MaxBytesReader) before Request.ParseForm is called
+
+	TrimSpace        bool // if true then leading and trailing white space is removed from values before parsing (except values for string fields)
+	TrimSpaceStrings bool // if true then leading and trailing white space is removed from values for string fields too (requires TrimSpace)
+
+	ThousandsSeparator rune // if not 0 then it is removed from values for numeric fields before parsing (i.e. ',' for "1,234.56"), grouping itself is not checked
+	DecimalMark        rune // if not 0 then it is replaced with '.' in values for numeric fields before parsing (i.e. ',' for "1.234,56")
+
+	PortableIntSize bool // if true then int & uint fields accept only values which fit into 32 bits (so behaviour is the same on 32- & 64-bit platforms)
+
+	AbsoluteURL bool // if true then only absolute URLs (with scheme and host) are valid values for url.URL fields
+
+	MaxValueLen     int // if not 0 then values longer than MaxValueLen bytes are rejected (with ScanErrorTypeLimitExceeded) before parsing
+	MaxFieldRepeats int // if not 0 then fields with more than MaxFieldRepeats values in form are rejected (with ScanErrorTypeLimitExceeded) before parsing
+
+	ContinueOnError bool // if true then scanning does not stop on first error: all fields are scanned (successfully scanned fields are assigned while variables of failed fields leave untouched, so on error variables are partially modified) and all errors are returned as ScanErrors (use ScanErrors.Failed to check which fields failed)
+
+	DryRun bool // if true then fields are parsed and validated as usual but results are discarded, so variables pointed by fields' Value are not modified (only error is returned)
+
+	NestedSeparator string // separator between name of nested struct and name of its field in form field name for BindForm (if empty then "." is used, i.e. "address.city")
+}
+
+// parseURL parses s as URL (checking it is absolute if AbsoluteURL is set).
+func (o *ScanOptions) parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if o.AbsoluteURL && (u.Scheme == "" || u.Host == "") {
+		return nil, errors.New("'" + s + "' is not an absolute URL.")
+	}
+	return u, nil
+}
+
+// parseBool parses s as bool using TrueValues & FalseValues (or using strconv.ParseBool if StrconvBool is set).
+func (o *ScanOptions) parseBool(s string) (bool, error) {
+	if o.StrconvBool {
+		return strconv.ParseBool(s)
+	}
+	trueValues := o.TrueValues
+	if len(trueValues) == 0 {
+		trueValues = []string{scanBoolTrueString}
+	}
+	falseValues := o.FalseValues
+	if len(falseValues) == 0 {
+		falseValues = []string{scanBoolFalseString}
+	}
+
+	if o.boolOneOf(s, trueValues) {
+		return true, nil
+	}
+	if o.boolOneOf(s, falseValues) {
+		return false, nil
+	}
+	return false, errors.New("'" + s + "' is not a valid bool value.")
+}
+
+// boolOneOf checks if s is one of given tokens (respecting CaseInsensitiveBool).
+func (o *ScanOptions) boolOneOf(s string, tokens []string) bool {
+	for _, t := range tokens {
+		if s == t || (o.CaseInsensitiveBool && strings.EqualFold(s, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanForm scans form for all fields and stops on first error (unless ContinueOnError is set).
+func (o *ScanOptions) scanForm(form url.Values, files map[string][]*multipart.FileHeader, fields []ScanField) error {
+	return o.scanFormContext(context.Background(), form, files, fields)
+}
+
+// scanFormContext scans form for all fields and stops on first error (unless ContinueOnError is set) or if ctx is done.
+func (o *ScanOptions) scanFormContext(ctx context.Context, form url.Values, files map[string][]*multipart.FileHeader, fields []ScanField) error {
+	var errs ScanErrors
+	for i, field := range fields {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := o.scanField(form, files, i, field); err != nil {
+			if !o.ContinueOnError {
+				return err
+			}
+			errs = append(errs, err.(ScanError))
+		}
+	}
+	if o.StrictUnknown {
+		if err := o.checkUnexpected(form, fields); err != nil {
+			if !o.ContinueOnError {
+				return err
+			}
+			errs = append(errs, err.(ScanError))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkUnexpected checks that form does not contain fields other than requested.
+// If there are several unexpected fields then the first one (in lexicographical order) is reported.
+func (o *ScanOptions) checkUnexpected(form url.Values, fields []ScanField) error {
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		expected := false
+		for i := range fields {
+			if o.matchName(key, &fields[i]) {
+				expected = true
+				break
+			}
+		}
+		if !expected {
+			return scanErrorUnexpectedField(key, form[key])
+		}
+	}
+	return nil
+}
+
+// matchName checks if key in form matches field name or any of its aliases (respecting CaseInsensitiveNames).
+func (o *ScanOptions) matchName(key string, field *ScanField) bool {
+	if key == field.Name || (o.CaseInsensitiveNames && strings.EqualFold(key, field.Name)) {
+		return true
+	}
+	for _, alias := range field.Aliases {
+		if key == alias || (o.CaseInsensitiveNames && strings.EqualFold(key, alias)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanField scans form (or files for file fields) for single field.
+// Returned ScanError contains Label & Message of field.
+func (o *ScanOptions) scanField(form url.Values, files map[string][]*multipart.FileHeader, fieldNum int, field ScanField) error {
+	err := o.scanFieldValue(form, files, fieldNum, field)
+	if err != nil && (field.Label != "" || field.Message != "") {
+		se := err.(ScanError)
+		se.Label, se.Message = field.Label, field.Message
+		return se
+	}
+	return err
+}
+
+// scanFieldValue does the same as scanField but does not add Label & Message to error.
+func (o *ScanOptions) scanFieldValue(form url.Values, files map[string][]*multipart.FileHeader, fieldNum int, field ScanField) error {
+	if o.DryRun {
+		field.Value = dryRunValue(field.Value)
+	}
+	if field.Type != nil {
+		return o.scanTyped(form, files, fieldNum, field)
+	}
+	if fh, isFile := field.Value.(**multipart.FileHeader); isFile {
+		return o.scanFile(files, fieldNum, field, fh)
+	}
+
+	stringValues, ok := lookup(o, form, &field)
+	if err := o.checkLimits(fieldNum, &field, stringValues); err != nil {
+		return err
+	}
+	if b, isBool := field.Value.(*bool); isBool && field.Checkbox {
+		*b = len(nonEmpty(stringValues)) > 0
+		return nil
+	}
+	if ok && o.TreatEmptyAsAbsent {
+		stringValues = nonEmpty(stringValues)
+		ok = len(stringValues) > 0
+	}
+	sv, isSlice := sliceTarget(&field)
+
+	if !ok {
+		if field.Default != nil {
+			if !setDefault(field.Value, field.Default) {
+				return scanErrorIncompatibleType(fieldNum, field.Name, "")
+			}
+			return nil
+		}
+		if b, isBool := field.Value.(*bool); isBool && o.AbsentBoolIsFalse {
+			*b = false
+			return nil
+		}
+		if field.Optional {
+			setNil(field.Value)
+			return nil
+		}
+		if !isSlice {
+			return scanErrorNoSuchField(fieldNum, field.Name)
+		}
+	}
+
+	if isSlice {
+		return o.scanSlice(fieldNum, &field, stringValues, sv)
+	}
+	if av, isArray := arrayTarget(&field); isArray {
+		return o.scanArray(fieldNum, &field, stringValues, av)
+	}
+
+	if len(stringValues) > 1 {
+		switch {
+		case o.FirstValueWins:
+			stringValues = stringValues[:1]
+		case o.LastValueWins:
+			stringValues = stringValues[len(stringValues)-1:]
+		}
+	}
+	if len(stringValues) != 1 {
+		return scanErrorMultipleValues(fieldNum, field.Name, stringValues)
+	}
+	if o.EmptyValueError && stringValues[0] == "" && !field.Optional && field.Default == nil {
+		return scanErrorEmptyValue(fieldNum, field.Name)
+	}
+	return o.scanValue(fieldNum, &field, stringValues[0], field.Value)
+}
+
+// checkLimits checks that stringValues do not exceed MaxFieldRepeats & MaxValueLen.
+func (o *ScanOptions) checkLimits(fieldNum int, field *ScanField, stringValues []string) error {
+	if o.MaxFieldRepeats != 0 && len(stringValues) > o.MaxFieldRepeats {
+		return scanErrorLimitExceeded(fieldNum, field.Name, "", fmt.Errorf("field has %d values, maximum is %d.", len(stringValues), o.MaxFieldRepeats))
+	}
+	if o.MaxValueLen != 0 {
+		for _, stringValue := range stringValues {
+			if len(stringValue) > o.MaxValueLen {
+				return scanErrorLimitExceeded(fieldNum, field.Name, "", fmt.Errorf("value length %d is greater than maximum %d.", len(stringValue), o.MaxValueLen))
+			}
+		}
+	}
+	return nil
+}
+
+// scanTyped scans field with Type: value is parsed into new variable of field.Type and stored to interface pointed by field.Value.
+// If field is optional and absent (without Default) then interface leaves untouched.
+func (o *ScanOptions) scanTyped(form url.Values, files map[string][]*multipart.FileHeader, fieldNum int, field ScanField) error {
+	iv, ok := field.Value.(*interface{})
+	if !ok || iv == nil {
+		return ScanError{FieldNum: fieldNum, FieldName: field.Name, Type: ScanErrorTypeIncompatibleType, SubError: errors.New("Value should be a non-nil *interface{} if Type is set."), ElementIndex: -1}
+	}
+	nv := reflect.New(field.Type)
+	field.Value, field.Type = nv.Interface(), nil
+	present := o.present(form, files, &field)
+	if err := o.scanFieldValue(form, files, fieldNum, field); err != nil {
+		return err
+	}
+	if present || !field.Optional || field.Default != nil {
+		*iv = nv.Elem().Interface()
+	}
+	return nil
+}
+
+// dryRunValue returns pointer to new variable of the same type as variable pointed by value (so scanning to it does not modify original variable).
+func dryRunValue(value interface{}) interface{} {
+	if tv, ok := value.(timeValue); ok {
+		return timeValue{value: new(time.Time), layouts: tv.layouts}
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() {
+		return reflect.New(v.Elem().Type()).Interface()
+	}
+	return value
+}
+
+// jsonNumber checks if s is a number in JSON grammar (so "NaN", "+1", "01" & "0x10" are not numbers).
+func jsonNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) || s[len(s)-1] < '0' || s[len(s)-1] > '9' {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// tempValue returns temporary variable of the same type as value and function which copies temporary variable to value.
+// For big.Float precision & rounding mode of value are kept. If value is not a non-nil pointer then value itself is returned.
+func tempValue(value interface{}) (tmp interface{}, store func()) {
+	switch v := value.(type) {
+	case timeValue:
+		t := new(time.Time)
+		return timeValue{value: t, layouts: v.layouts}, func() { *v.value = *t }
+	case *big.Float:
+		f := new(big.Float).SetPrec(v.Prec()).SetMode(v.Mode())
+		return f, func() { v.Set(f) }
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return value, func() {}
+	}
+	pv := reflect.New(v.Elem().Type())
+	return pv.Interface(), func() { v.Elem().Set(pv.Elem()) }
+}
+
+// present checks if there is value (or file for file fields) for field in form.
+func (o *ScanOptions) present(form url.Values, files map[string][]*multipart.FileHeader, field *ScanField) bool {
+	if _, isFile := field.Value.(**multipart.FileHeader); isFile {
+		_, ok := lookup(o, files, field)
+		return ok
+	}
+	stringValues, ok := lookup(o, form, field)
+	if ok && o.TreatEmptyAsAbsent {
+		ok = len(nonEmpty(stringValues)) > 0
+	}
+	return ok
+}
+
+// nonEmpty returns all non-empty values from stringValues.
+func nonEmpty(stringValues []string) []string {
+	result := make([]string, 0, len(stringValues))
+	for _, stringValue := range stringValues {
+		if stringValue != "" {
+			result = append(result, stringValue)
+		}
+	}
+	return result
+}
+
+// lookup returns values from m for field.
+// Values are looked up by field.Name and field.Aliases (respecting CaseInsensitiveNames), values for all matched keys are combined.
+func lookup[V any](o *ScanOptions, m map[string][]V, field *ScanField) ([]V, bool) {
+	if len(field.Aliases) == 0 && !o.CaseInsensitiveNames {
+		values, ok := m[field.Name]
+		return values, ok
+	}
+
+	names := append([]string{field.Name}, field.Aliases...)
+	var keys []string
+	if o.CaseInsensitiveNames {
+		keys = make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	// Each form key is consumed only once, even if it matches several names (i.e. name & alias differing only in case).
+	used := make(map[string]bool, len(names))
+	var values []V
+	var found bool
+	for _, name := range names {
+		if !o.CaseInsensitiveNames {
+			if vs, ok := m[name]; ok && !used[name] {
+				used[name] = true
+				values = append(values, vs...)
+				found = true
+			}
+			continue
+		}
+		for _, key := range keys {
+			if !used[key] && strings.EqualFold(key, name) {
+				used[key] = true
+				values = append(values, m[key]...)
+				found = true
+			}
+		}
+	}
+	return values, found
+}
+
+// scanFile scans files for single file field.
+func (o *ScanOptions) scanFile(files map[string][]*multipart.FileHeader, fieldNum int, field ScanField, fh **multipart.FileHeader) error {
+	headers, ok := lookup(o, files, &field)
+	if !ok {
+		if field.Optional {
+			*fh = nil
+			return nil
+		}
+		return scanErrorNoSuchField(fieldNum, field.Name)
+	}
+	if len(headers) != 1 {
+		filenames := make([]string, len(headers))
+		for i, h := range headers {
+			filenames[i] = h.Filename
+		}
+		return scanErrorMultipleValues(fieldNum, field.Name, filenames)
+	}
+	*fh = headers[0]
+	return nil
+}
+
+// ScanRangeMaxLen is a maximum number of elements in single range expanded for ScanField with Ranges set.
+const ScanRangeMaxLen = 1 << 16
+
+// expand applies Split, SplitSpace & Ranges of f to stringValues for slice (or array) field with element type elem.
+func (f *ScanField) expand(fieldNum int, stringValues []string, elem reflect.Type) ([]string, error) {
+	if f.Split {
+		stringValues = f.split(stringValues)
+	}
+	if f.SplitSpace {
+		parts := make([]string, 0, len(stringValues))
+		for _, stringValue := range stringValues {
+			parts = append(parts, strings.Fields(stringValue)...)
+		}
+		stringValues = parts
+	}
+	if !f.Ranges || !(isIntKind(elem.Kind()) || isUintKind(elem.Kind())) {
+		return stringValues, nil
+	}
+	result := make([]string, 0, len(stringValues))
+	for _, stringValue := range stringValues {
+		i := strings.Index(strings.TrimPrefix(stringValue, "-"), "-") // minus sign of first number is not a separator
+		if i < 0 {
+			result = append(result, stringValue)
+			continue
+		}
+		i += len(stringValue) - len(strings.TrimPrefix(stringValue, "-"))
+		// Bounds are parsed with field's base and expanded numbers are formatted back with the same base (decimal for ScanBaseAuto), so they are parsed the same way as other values.
+		var err error
+		if result, err = f.expandRange(fieldNum, stringValue, stringValue[:i], stringValue[i+1:], isUintKind(elem.Kind()), result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// expandRange appends all numbers from inclusive range "from-to" (stringValue) to result.
+// Bounds are parsed as unsigned numbers if unsigned is set (so range may contain numbers greater than math.MaxInt64).
+func (f *ScanField) expandRange(fieldNum int, stringValue, from, to string, unsigned bool, result []string) ([]string, error) {
+	if unsigned {
+		a, err1 := strconv.ParseUint(from, f.intBase(), 64)
+		b, err2 := strconv.ParseUint(to, f.intBase(), 64)
+		if err := f.checkRange(fieldNum, stringValue, err1 == nil && err2 == nil, a <= b, b-a); err != nil {
+			return nil, err
+		}
+		for n := a; ; n++ {
+			result = append(result, strconv.FormatUint(n, f.formatBase()))
+			if n == b {
+				return result, nil
+			}
+		}
+	}
+	a, err1 := strconv.ParseInt(from, f.intBase(), 64)
+	b, err2 := strconv.ParseInt(to, f.intBase(), 64)
+	if err := f.checkRange(fieldNum, stringValue, err1 == nil && err2 == nil, a <= b, uint64(b-a)); err != nil {
+		return nil, err
+	}
+	for n := a; ; n++ {
+		result = append(result, strconv.FormatInt(n, f.formatBase()))
+		if n == b {
+			return result, nil
+		}
+	}
+}
+
+// checkRange checks that range stringValue has valid bounds (valid), its start is not greater than its end (ordered) and length (difference between bounds) is less than ScanRangeMaxLen.
+func (f *ScanField) checkRange(fieldNum int, stringValue string, valid, ordered bool, length uint64) error {
+	switch {
+	case !valid:
+		return scanErrorIncompatibleValue(fieldNum, f.Name, stringValue, errors.New("'"+stringValue+"' is not a valid range (should be \"from-to\", i.e. \"10-20\")."))
+	case !ordered:
+		return scanErrorIncompatibleValue(fieldNum, f.Name, stringValue, errors.New("'"+stringValue+"' is not a valid range: start is greater than end."))
+	case length >= ScanRangeMaxLen:
+		return scanErrorIncompatibleValue(fieldNum, f.Name, stringValue, fmt.Errorf("'%s' is a too long range (maximum is %d elements).", stringValue, ScanRangeMaxLen))
+	}
+	return nil
+}
+
+// separator returns f.Separator or "," if it is empty.
+func (f *ScanField) separator() string {
+	if f.Separator == "" {
+		return ","
+	}
+	return f.Separator
+}
+
+// split splits each of stringValues by f.Separator and returns all parts. Empty values produce no parts.
+func (f *ScanField) split(stringValues []string) []string {
+	sep := f.separator()
+	parts := make([]string, 0, len(stringValues))
+	for _, stringValue := range stringValues {
+		if stringValue != "" {
+			parts = append(parts, strings.Split(stringValue, sep)...)
+		}
+	}
+	return parts
+}
+
+// sliceTarget checks if field.Value is a pointer to slice and returns reflect.Value of pointed slice if so.
+// Slice types which are scanned from single value (i.e. net.IP or []byte with Encoding) are not treated as slices.
+func sliceTarget(field *ScanField) (reflect.Value, bool) {
+	switch field.Value.(type) {
+	case *net.IP, *json.RawMessage, FormScanner, encoding.TextUnmarshaler, encoding.BinaryUnmarshaler:
+		return reflect.Value{}, false
+	case *[]byte:
+		if field.Encoding != ScanEncodingNone {
+			return reflect.Value{}, false
+		}
+	}
+	v := reflect.ValueOf(field.Value)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, false
+	}
+	return v.Elem(), true
+}
+
+// arrayTarget checks if field.Value is a pointer to array and returns reflect.Value of pointed array if so.
+// Array types which are scanned from single value (i.e. implementing encoding.TextUnmarshaler) are not treated as arrays.
+func arrayTarget(field *ScanField) (reflect.Value, bool) {
+	if field.UUID {
+		return reflect.Value{}, false
+	}
+	switch field.Value.(type) {
+	case FormScanner, encoding.TextUnmarshaler, encoding.BinaryUnmarshaler:
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(field.Value)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Array {
+		return reflect.Value{}, false
+	}
+	return v.Elem(), true
+}
+
+// elementError adds index i of slice or array element to err (ElementIndex is set and SubError is prefixed with "value #i").
+// Incompatible type error is returned as-is because it is not related to particular element.
+func elementError(err error, i int) error {
+	se := err.(ScanError)
+	if se.Type == ScanErrorTypeIncompatibleType {
+		return err
+	}
+	se.ElementIndex = i
+	if se.SubError != nil {
+		se.SubError = fmt.Errorf("value #%d: %w", i, se.SubError)
+	}
+	return se
+}
+
+// scanArray parses each of stringValues and stores all results to array av.
+// Number of values should be exactly the same as length of array, otherwise error with type ScanErrorTypeValueCountMismatch is returned.
+// Array is modified only if all values are valid.
+func (o *ScanOptions) scanArray(fieldNum int, field *ScanField, stringValues []string, av reflect.Value) error {
+	stringValues, err := field.expand(fieldNum, stringValues, av.Type().Elem())
+	if err != nil {
+		return err
+	}
+	if len(stringValues) != av.Len() {
+		return scanErrorValueCountMismatch(fieldNum, field.Name, stringValues, fmt.Errorf("field has %d values, but exactly %d values required.", len(stringValues), av.Len()))
+	}
+	result := reflect.New(av.Type()).Elem()
+	for i, stringValue := range stringValues {
+		if err := o.scanValue(fieldNum, field, stringValue, result.Index(i).Addr().Interface()); err != nil {
+			return elementError(err, i)
+		}
+	}
+	av.Set(result)
+	return nil
+}
+
+// scanSlice parses each of stringValues and stores all results to slice sv (sv will be replaced, not appended).
+// If some of stringValues is invalid, its index will be reported in SubError.
+func (o *ScanOptions) scanSlice(fieldNum int, field *ScanField, stringValues []string, sv reflect.Value) error {
+	stringValues, err := field.expand(fieldNum, stringValues, sv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	result := reflect.MakeSlice(sv.Type(), 0, len(stringValues))
+	for i, stringValue := range stringValues {
+		ev := reflect.New(sv.Type().Elem())
+		if err := o.scanValue(fieldNum, field, stringValue, ev.Interface()); err != nil {
+			return elementError(err, i)
+		}
+		result = reflect.Append(result, ev.Elem())
+	}
+	sv.Set(result)
+	return nil
+}
+
+// scanValue parses stringValue, stores result to variable pointed by value and validates it.
+// Parsing and validating parameters (such as Base & Min) are taken from field, but value is not required to be field.Value (i.e. it may be a slice element).
+func (o *ScanOptions) scanValue(fieldNum int, field *ScanField, stringValue string, value interface{}) error {
+	if field.Transform != nil {
+		stringValue = field.Transform(stringValue)
+	}
+	// Value is parsed & validated in temporary variable, so invalid or rejected value does not modify variable pointed by value.
+	tmp, store := tempValue(value)
+	if err := o.parseValue(fieldNum, field, stringValue, tmp); err != nil {
+		return err
+	}
+	if err := field.validate(fieldNum, stringValue, tmp); err != nil {
+		return err
+	}
+	store()
+	return nil
+}
+
+// parseValue parses stringValue and stores result to variable pointed by value.
+func (o *ScanOptions) parseValue(fieldNum int, field *ScanField, stringValue string, value interface{}) error {
+	if inner, valid, isNull := nullValue(value); isNull {
+		if err := o.parseValue(fieldNum, field, stringValue, inner); err != nil {
+			return err
+		}
+		*valid = true
+		return nil
+	}
+	fieldName := field.Name
+	if o.TrimSpace {
+		if _, isString := value.(*string); !isString || o.TrimSpaceStrings {
+			stringValue = strings.TrimSpace(stringValue)
+		}
+	}
+	if fs, ok := value.(FormScanner); ok {
+		if err := fs.ScanForm(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		return nil
+	}
+	if field.Char {
+		if ok, err := scanChar(stringValue, value); ok {
+			if err != nil {
+				return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+			}
+			return nil
+		}
+	}
+	if d, ok := value.(*time.Duration); ok && field.DurationUnit != 0 {
+		var err error
+		if *d, err = parseDurationUnit(stringValue, field.DurationUnit); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		return nil
+	}
+	if uv, ok := uuidTarget(value); ok && field.UUID {
+		u, err := parseUUID(stringValue)
+		if err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		reflect.Copy(uv, reflect.ValueOf(u[:]))
+		return nil
+	}
+	if b, ok := value.(*[]byte); ok && field.Encoding != ScanEncodingNone {
+		var err error
+		if *b, err = field.Encoding.decode(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		return nil
+	}
+	if bu, ok := value.(encoding.BinaryUnmarshaler); ok && field.Encoding != ScanEncodingNone {
+		data, err := field.Encoding.decode(stringValue)
+		if err == nil {
+			err = bu.UnmarshalBinary(data)
+		}
+		if err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		return nil
+	}
+	if (o.ThousandsSeparator != 0 || o.DecimalMark != 0) && numberTarget(value) {
+		stringValue = o.normalizeNumber(stringValue)
+	}
+	if strings.HasPrefix(stringValue, "-") && unsignedTarget(value) {
+		return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, errors.New("'"+stringValue+"' is negative, but field is unsigned (minus sign is not allowed even for zero)."))
+	}
+	if o.PortableIntSize {
+		if ok, err := scanIntPortable(stringValue, field.intBase(), value); ok {
+			if err != nil {
+				return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+			}
+			return nil
+		}
+	}
+	if base := field.intBase(); base != 10 {
+		if ok, err := scanIntBase(stringValue, base, value); ok {
+			if err != nil {
+				return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+			}
+			return nil
+		}
+	}
+
+	// Concrete types are matched first (fast path without reflection), reflection is used only if none of them matches
+	var err error
+	switch v := value.(type) {
+	case *int:
+		if *v, err = strconvhelper.ParseInt(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *int8:
+		if *v, err = strconvhelper.ParseInt8(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *int16:
+		if *v, err = strconvhelper.ParseInt16(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *int32:
+		if *v, err = strconvhelper.ParseInt32(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *int64:
+		if *v, err = strconvhelper.ParseInt64(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *uint:
+		if *v, err = strconvhelper.ParseUint(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *uint8:
+		if *v, err = strconvhelper.ParseUint8(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *uint16:
+		if *v, err = strconvhelper.ParseUint16(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *uint32:
+		if *v, err = strconvhelper.ParseUint32(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *uint64:
+		if *v, err = strconvhelper.ParseUint64(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *float32:
+		var f float64
+		if f, err = strconv.ParseFloat(stringValue, 32); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = float32(f)
+	case *float64:
+		if *v, err = strconv.ParseFloat(stringValue, 64); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *complex64:
+		var c complex128
+		if c, err = strconv.ParseComplex(stringValue, 64); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = complex64(c)
+	case *complex128:
+		if *v, err = strconv.ParseComplex(stringValue, 128); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *bool:
+		if *v, err = o.parseBool(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *string:
+		*v = stringValue
+	case *time.Time:
+		if *v, err = parseTime(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case timeValue:
+		if *v.value, err = parseTime(stringValue, v.layouts...); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *time.Duration:
+		if *v, err = time.ParseDuration(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *time.Weekday:
+		var n int
+		if n, err = parseCalendarName(stringValue, int(time.Sunday), int(time.Saturday), func(i int) string { return time.Weekday(i).String() }); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = time.Weekday(n)
+	case *time.Month:
+		var n int
+		if n, err = parseCalendarName(stringValue, int(time.January), int(time.December), func(i int) string { return time.Month(i).String() }); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = time.Month(n)
+	case *net.IP:
+		ip := net.ParseIP(stringValue)
+		if ip == nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, &net.ParseError{Type: "IP address", Text: stringValue})
+		}
+		*v = ip
+	case *net.IPNet:
+		var ipNet *net.IPNet
+		if _, ipNet, err = net.ParseCIDR(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = *ipNet
+	case *netip.Addr:
+		if *v, err = netip.ParseAddr(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *netip.Prefix:
+		if *v, err = netip.ParsePrefix(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case *url.URL:
+		var u *url.URL
+		if u, err = o.parseURL(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = *u
+	case *mail.Address:
+		var addr *mail.Address
+		if addr, err = mail.ParseAddress(stringValue); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+		*v = *addr
+	case *json.Number:
+		n := json.Number(stringValue)
+		if !jsonNumber(stringValue) {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, errors.New("'"+stringValue+"' is not a valid JSON number."))
+		}
+		if _, err = n.Int64(); err != nil {
+			if _, err = n.Float64(); err != nil {
+				return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+			}
+		}
+		*v = n
+	case *json.RawMessage:
+		if !json.Valid([]byte(stringValue)) {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, errors.New("'"+stringValue+"' is not a valid JSON."))
+		}
+		*v = json.RawMessage(stringValue)
+	case *big.Int:
+		// big.Int.SetString panics on invalid base while strconv returns error, so check it here.
+		if base := field.intBase(); base != 0 && (base < 2 || base > 36) {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, fmt.Errorf("invalid base %d.", field.Base))
+		}
+		if _, ok := v.SetString(stringValue, field.intBase()); !ok {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, errors.New("'"+stringValue+"' is not a valid integer value."))
+		}
+	case *big.Float:
+		if _, ok := v.SetString(stringValue); !ok {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, errors.New("'"+stringValue+"' is not a valid float value."))
+		}
+	case *big.Rat:
+		if _, ok := v.SetString(stringValue); !ok {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, errors.New("'"+stringValue+"' is not a valid rational value."))
+		}
+	case encoding.TextUnmarshaler:
+		if err = v.UnmarshalText([]byte(stringValue)); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	case encoding.BinaryUnmarshaler:
+		if err = v.UnmarshalBinary([]byte(stringValue)); err != nil {
+			return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+		}
+	default:
+		// Named numeric types (i.e. type UserID int64) and uintptr
+		if ok, err := o.scanNumberKind(stringValue, field.intBase(), value); ok {
+			if err != nil {
+				return scanErrorIncompatibleValue(fieldNum, fieldName, stringValue, err)
+			}
+			return nil
+		}
+		// Pointer to pointer: allocate new variable, scan to it and store pointer to it
+		if pv := reflect.ValueOf(value); pv.Kind() == reflect.Ptr && !pv.IsNil() && pv.Elem().Kind() == reflect.Ptr {
+			nv := reflect.New(pv.Elem().Type().Elem())
+			if err := o.parseValue(fieldNum, field, stringValue, nv.Interface()); err != nil {
+				return err
+			}
+			pv.Elem().Set(nv)
+			return nil
+		}
+		return scanErrorIncompatibleType(fieldNum, fieldName, stringValue)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package httphelper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanBigIntInvalidBase(t *testing.T) {
+	for _, base := range []int{1, 37, 100, -2} {
+		var n big.Int
+		err := ScanValues(url.Values{"n": {"10"}}, ScanField{Name: "n", Value: &n, Base: base})
+		if !errors.Is(err, ErrIncompatibleValue) {
+			t.Errorf("base %d: expected incompatible value error, got %v", base, err)
+		}
+	}
+
+	var n big.Int
+	if err := ScanValues(url.Values{"n": {"z"}}, ScanField{Name: "n", Value: &n, Base: 36}); err != nil || n.Int64() != 35 {
+		t.Errorf("base 36: expected 35, got %v (error %v)", &n, err)
+	}
+}
+
+func TestScanChar(t *testing.T) {
+	tests := []struct {
+		value string
+		r     rune
+		ok    bool
+	}{
+		{"a", 'a', true},
+		{"ж", 'ж', true},
+		{"�", '�', true},
+		{"\xff", 0, false},
+		{"ab", 0, false},
+		{"", 0, false},
+	}
+	for _, test := range tests {
+		var r rune
+		err := ScanValues(url.Values{"c": {test.value}}, ScanField{Name: "c", Value: &r, Char: true})
+		if test.ok && (err != nil || r != test.r) {
+			t.Errorf("%q: expected %q, got %q (error %v)", test.value, test.r, r, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%q: expected error, got %q", test.value, r)
+		}
+	}
+}
+
+func TestScanAliasSameKey(t *testing.T) {
+	r := newFormRequest(t, "email=a@b")
+	for _, options := range []ScanOptions{{}, {CaseInsensitiveNames: true}} {
+		for _, aliases := range [][]string{{"email"}, {"Email"}} {
+			var s string
+			err := ScanFormDataWithOptions(r, options, ScanField{Name: "email", Value: &s, Aliases: aliases})
+			if err != nil || s != "a@b" {
+				t.Errorf("case insensitive %v, aliases %q: expected 'a@b', got %q (error %v)", options.CaseInsensitiveNames, aliases, s, err)
+			}
+		}
+	}
+}
+
+func TestScanPlatformIntSize(t *testing.T) {
+	// Result depends on platform, so tests should be run for both widths (i.e. with GOARCH=386 & GOARCH=amd64).
+	wide := strconv.IntSize == 64
+	var i int
+	var u uint
+	fields := []ScanField{{Name: "i", Value: &i}, {Name: "u", Value: &u}}
+	for _, field := range fields {
+		err := ScanValues(url.Values{"i": {"2147483648"}, "u": {"4294967296"}}, field)
+		if (err == nil) != wide {
+			t.Errorf("%T on %d-bit platform: expected ok %v, got error %v", field.Value, strconv.IntSize, wide, err)
+		}
+		err = ScanFormDataWithOptions(newFormRequest(t, "i=2147483648&u=4294967296"), ScanOptions{PortableIntSize: true}, field)
+		if !errors.Is(err, ErrOverflow) {
+			t.Errorf("%T with PortableIntSize on %d-bit platform: expected overflow error, got %v", field.Value, strconv.IntSize, err)
+		}
+	}
+}
+
+func TestScanPortableIntSize(t *testing.T) {
+	type count int
+	options := ScanOptions{PortableIntSize: true}
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"2147483647", true},
+		{"-2147483648", true},
+		{"2147483648", false},
+		{"-2147483649", false},
+	}
+	for _, test := range tests {
+		var i int
+		var c count
+		r := newFormRequest(t, "i="+url.QueryEscape(test.value)+"&c="+url.QueryEscape(test.value))
+		if err := ScanFormDataWithOptions(r, options, ScanField{Name: "i", Value: &i}); (err == nil) != test.ok {
+			t.Errorf("int %s: expected ok %v, got error %v", test.value, test.ok, err)
+		}
+		if err := ScanFormDataWithOptions(r, options, ScanField{Name: "c", Value: &c}); (err == nil) != test.ok {
+			t.Errorf("named int %s: expected ok %v, got error %v", test.value, test.ok, err)
+		}
+	}
+
+	var u uint
+	if err := ScanFormDataWithOptions(newFormRequest(t, "u=4294967295"), options, ScanField{Name: "u", Value: &u}); err != nil || u != 4294967295 {
+		t.Errorf("uint 4294967295: expected success, got %v (error %v)", u, err)
+	}
+	if err := ScanFormDataWithOptions(newFormRequest(t, "u=4294967296"), options, ScanField{Name: "u", Value: &u}); err == nil {
+		t.Errorf("uint 4294967296: expected error, got %v", u)
+	}
+	var i int
+	if err := ScanFormDataWithOptions(newFormRequest(t, "i=0x80000000"), options, ScanField{Name: "i", Value: &i, Base: ScanBaseAuto}); err == nil {
+		t.Errorf("int 0x80000000: expected error, got %v", i)
+	}
+}
+
+// UserID is a named integer type, it is scanned using reflection.
+type UserID int64
+
+var benchmarkValues = url.Values{"id": {"123456"}}
+
+func BenchmarkScanInt(b *testing.B) {
+	var id int
+	for i := 0; i < b.N; i++ {
+		if err := ScanValues(benchmarkValues, ScanField{Name: "id", Value: &id}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScanString(b *testing.B) {
+	var id string
+	for i := 0; i < b.N; i++ {
+		if err := ScanValues(benchmarkValues, ScanField{Name: "id", Value: &id}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScanNamedInt(b *testing.B) {
+	var id UserID
+	for i := 0; i < b.N; i++ {
+		if err := ScanValues(benchmarkValues, ScanField{Name: "id", Value: &id}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestScanNegativeUnsigned(t *testing.T) {
+	type flags uint16
+	var (
+		u   uint
+		u8  uint8
+		u16 uint16
+		u32 uint32
+		u64 uint64
+		up  uintptr
+		f   flags
+	)
+	for _, value := range []interface{}{&u, &u8, &u16, &u32, &u64, &up, &f} {
+		for _, s := range []string{"-1", "-0"} {
+			err := ScanValues(url.Values{"n": {s}}, ScanField{Name: "n", Value: value})
+			if !errors.Is(err, ErrIncompatibleValue) {
+				t.Errorf("%T %s: expected incompatible value error, got %v", value, s, err)
+			}
+		}
+	}
+}
+
+func TestScanDurationThousandsSeparator(t *testing.T) {
+	options := ScanOptions{ThousandsSeparator: '.', DecimalMark: ','}
+	var d time.Duration
+	var n int
+	r := newFormRequest(t, "d=1.5s&n=1.500")
+	if err := ScanFormDataWithOptions(r, options, ScanField{Name: "d", Value: &d}, ScanField{Name: "n", Value: &n}); err != nil {
+		t.Fatal(err)
+	}
+	if d != 1500*time.Millisecond || n != 1500 {
+		t.Errorf("expected 1.5s & 1500, got %v & %v", d, n)
+	}
+}
+
+func TestScanTriStateBool(t *testing.T) {
+	tests := []struct {
+		query   string
+		present bool
+		value   bool
+	}{
+		{"b=on", true, true},
+		{"b=off", true, false},
+		{"", false, false},
+		{"b=", false, false},
+	}
+	options := ScanOptions{TreatEmptyAsAbsent: true}
+	for _, test := range tests {
+		r := newFormRequest(t, test.query)
+
+		old := true
+		pb := &old
+		if err := ScanFormDataWithOptions(r, options, ScanField{Name: "b", Value: &pb, Optional: true}); err != nil {
+			t.Errorf("**bool %q: unexpected error %v", test.query, err)
+		} else if (pb != nil) != test.present || (pb != nil && *pb != test.value) {
+			t.Errorf("**bool %q: expected present %v & value %v, got %v", test.query, test.present, test.value, pb)
+		}
+
+		nb := sql.NullBool{Bool: true, Valid: true}
+		if err := ScanFormDataWithOptions(r, options, ScanField{Name: "b", Value: &nb, Optional: true}); err != nil {
+			t.Errorf("sql.NullBool %q: unexpected error %v", test.query, err)
+		} else if nb.Valid != test.present || (nb.Valid && nb.Bool != test.value) {
+			t.Errorf("sql.NullBool %q: expected present %v & value %v, got %+v", test.query, test.present, test.value, nb)
+		}
+	}
+}
+
+func TestScanRangesBase(t *testing.T) {
+	tests := []struct {
+		value  string
+		base   int
+		result []int
+	}{
+		{"1-3", 0, []int{1, 2, 3}},
+		{"-2-0", 0, []int{-2, -1, 0}},
+		{"a-c", 16, []int{10, 11, 12}},
+		{"-b--a", 16, []int{-11, -10}},
+		{"0x10-0x12", ScanBaseAuto, []int{16, 17, 18}},
+		{"0b1-3", ScanBaseAuto, []int{1, 2, 3}},
+	}
+	for _, test := range tests {
+		var result []int
+		err := ScanValues(url.Values{"n": {test.value}}, ScanField{Name: "n", Value: &result, Ranges: true, Base: test.base})
+		if err != nil || !reflect.DeepEqual(result, test.result) {
+			t.Errorf("%q (base %d): expected %v, got %v (error %v)", test.value, test.base, test.result, result, err)
+		}
+	}
+
+	unsignedTests := []struct {
+		value  string
+		base   int
+		result []uint64
+	}{
+		{"18446744073709551614-18446744073709551615", 0, []uint64{1<<64 - 2, 1<<64 - 1}},
+		{"fffffffffffffffe-ffffffffffffffff", 16, []uint64{1<<64 - 2, 1<<64 - 1}},
+		{"0x8000000000000000-0x8000000000000001", ScanBaseAuto, []uint64{1 << 63, 1<<63 + 1}},
+	}
+	for _, test := range unsignedTests {
+		var result []uint64
+		err := ScanValues(url.Values{"n": {test.value}}, ScanField{Name: "n", Value: &result, Ranges: true, Base: test.base})
+		if err != nil || !reflect.DeepEqual(result, test.result) {
+			t.Errorf("%q (base %d): expected %v, got %v (error %v)", test.value, test.base, test.result, result, err)
+		}
+	}
+
+	var u []uint
+	if err := ScanValues(url.Values{"n": {"3-1"}}, ScanField{Name: "n", Value: &u, Ranges: true}); err == nil || !strings.Contains(err.Error(), "start is greater than end") {
+		t.Errorf("expected start after end error, got %v", err)
+	}
+}
+
+func TestScanRejectedValueUntouched(t *testing.T) {
+	i, i8, s, ni := 1, int8(1), "old", sql.NullInt64{Int64: 1, Valid: true}
+	tests := []struct {
+		value     string
+		field     ScanField
+		untouched func() bool
+	}{
+		{"100", ScanField{Value: &i, Max: 10}, func() bool { return i == 1 }},
+		{"300", ScanField{Value: &i8}, func() bool { return i8 == 1 }},
+		{"ab", ScanField{Value: &s, MinLen: 3}, func() bool { return s == "old" }},
+		{"abc", ScanField{Value: &s, AllowedValues: []string{"x"}}, func() bool { return s == "old" }},
+		{"7", ScanField{Value: &ni, Validator: func(interface{}) error { return errors.New("rejected") }}, func() bool { return ni.Int64 == 1 && ni.Valid }},
+	}
+	for _, test := range tests {
+		test.field.Name = "v"
+		if err := ScanValues(url.Values{"v": {test.value}}, test.field); err == nil {
+			t.Errorf("%q into %T: expected error", test.value, test.field.Value)
+		}
+		if !test.untouched() {
+			t.Errorf("%q into %T: variable is modified by rejected value", test.value, test.field.Value)
+		}
+	}
+}
+
+func TestScanJSONNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"0", true},
+		{"-12", true},
+		{"1.5e10", true},
+		{"9223372036854775808", true},
+		{"NaN", false},
+		{"Inf", false},
+		{"+1", false},
+		{"01", false},
+		{"0x1p4", false},
+		{" 1", false},
+		{"1 ", false},
+		{"1.", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		var n json.Number
+		err := ScanValues(url.Values{"n": {test.value}}, ScanField{Name: "n", Value: &n})
+		if (err == nil) != test.ok {
+			t.Errorf("%q: expected ok %v, got error %v", test.value, test.ok, err)
+			continue
+		}
+		if test.ok {
+			if b, err := json.Marshal(n); err != nil || string(b) != test.value {
+				t.Errorf("%q: marshaled as %s (error %v)", test.value, b, err)
+			}
+		}
+	}
+}
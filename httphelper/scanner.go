@@ -0,0 +1,90 @@
+package httphelper
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Scanner scans requests using options given once at creation, so the same options may be reused across many requests.
+// Zero Scanner uses default options (the same as ScanFormData).
+// Scanner holds no per-call state and its options are never modified after creation (With* methods return copies), so it is safe to call methods of the same Scanner from multiple goroutines concurrently (i.e. from HTTP handlers).
+// Variables pointed by fields' Value are not protected, each concurrent call should use its own variables.
+type Scanner struct {
+	options ScanOptions
+}
+
+// NewScanner returns new Scanner which uses given options.
+func NewScanner(options ScanOptions) *Scanner {
+	return &Scanner{options: options}
+}
+
+// Options returns copy of options used by s (modifying it does not affect s).
+func (s *Scanner) Options() ScanOptions {
+	return s.clone().options
+}
+
+// clone returns copy of s (slices in options are copied too, so modifying copy does not affect s).
+func (s *Scanner) clone() *Scanner {
+	c := *s
+	c.options.TrueValues = append([]string(nil), s.options.TrueValues...)
+	c.options.FalseValues = append([]string(nil), s.options.FalseValues...)
+	return &c
+}
+
+// WithOptions returns new Scanner which uses given options (s is not modified).
+func (s *Scanner) WithOptions(options ScanOptions) *Scanner {
+	return NewScanner(options).clone()
+}
+
+// WithTrimSpace returns copy of s with TrimSpace option set to trimSpace (s is not modified).
+func (s *Scanner) WithTrimSpace(trimSpace bool) *Scanner {
+	c := s.clone()
+	c.options.TrimSpace = trimSpace
+	return c
+}
+
+// WithBoolTokens returns copy of s with TrueValues & FalseValues options set to trueValues & falseValues (s is not modified).
+func (s *Scanner) WithBoolTokens(trueValues, falseValues []string) *Scanner {
+	c := s.clone()
+	c.options.TrueValues = append([]string(nil), trueValues...)
+	c.options.FalseValues = append([]string(nil), falseValues...)
+	return c
+}
+
+// WithCaseInsensitiveNames returns copy of s with CaseInsensitiveNames option set to caseInsensitive (s is not modified).
+func (s *Scanner) WithCaseInsensitiveNames(caseInsensitive bool) *Scanner {
+	c := s.clone()
+	c.options.CaseInsensitiveNames = caseInsensitive
+	return c
+}
+
+// WithAutoParseForm returns copy of s with AutoParseForm option set to autoParseForm (s is not modified).
+func (s *Scanner) WithAutoParseForm(autoParseForm bool) *Scanner {
+	c := s.clone()
+	c.options.AutoParseForm = autoParseForm
+	return c
+}
+
+// WithStrictUnknown returns copy of s with StrictUnknown option set to strict (s is not modified).
+func (s *Scanner) WithStrictUnknown(strict bool) *Scanner {
+	c := s.clone()
+	c.options.StrictUnknown = strict
+	return c
+}
+
+// Scan scans Request.Form (or values selected by Source option) for fields using options of s.
+// It obeys all ScanFormData rules (but with options of s).
+// If options.AutoParseForm is set then returned error may also be of type ParseFormError.
+func (s *Scanner) Scan(r *http.Request, fields ...ScanField) error {
+	options := s.options
+	if err := options.parseForm(r); err != nil {
+		return err
+	}
+	return options.scanForm(options.requestForm(r), multipartFiles(r), fields)
+}
+
+// Encode does the same as EncodeFields but formats values using options of s (i.e. bools are formatted using the first of TrueValues & FalseValues).
+func (s *Scanner) Encode(fields ...ScanField) url.Values {
+	options := s.options
+	return options.encodeFields(fields)
+}
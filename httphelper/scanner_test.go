@@ -0,0 +1,52 @@
+package httphelper
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestScannerConcurrentScan(t *testing.T) {
+	s := NewScanner(ScanOptions{TrueValues: []string{"yes"}, FalseValues: []string{"no"}})
+	trimmed := s.WithTrimSpace(true)
+
+	const n = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 3*n)
+	for i := 0; i < n; i++ {
+		r := newFormRequest(t, "id="+strconv.Itoa(i)+"&ok=yes&num=+7+&Code=c")
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var id int
+			var ok bool
+			if err := s.Scan(r, ScanField{Name: "id", Value: &id}, ScanField{Name: "ok", Value: &ok}); err != nil {
+				errs <- err
+			} else if id != i || !ok {
+				errs <- fmt.Errorf("expected %d & true, got %d & %v", i, id, ok)
+			}
+
+			var num int
+			if err := trimmed.Scan(r, ScanField{Name: "num", Value: &num}); err != nil {
+				errs <- err
+			} else if num != 7 {
+				errs <- fmt.Errorf("expected 7, got %d", num)
+			}
+
+			// Derive scanner from shared one concurrently with other calls.
+			var code string
+			if err := s.WithCaseInsensitiveNames(true).WithBoolTokens([]string{"y"}, nil).Scan(r, ScanField{Name: "code", Value: &code}); err != nil {
+				errs <- err
+			} else if code != "c" {
+				errs <- fmt.Errorf("expected code 'c', got %q", code)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
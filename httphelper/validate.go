@@ -0,0 +1,188 @@
+package httphelper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// validate checks that variable pointed by value (parsed from stringValue) satisfies constraints of f and then calls f.Validator (if any).
+func (f *ScanField) validate(fieldNum int, stringValue string, value interface{}) error {
+	if err := f.validateConstraints(fieldNum, stringValue, value); err != nil {
+		return err
+	}
+	if f.Validator != nil {
+		if err := f.Validator(validatorValue(value)); err != nil {
+			return scanErrorValidation(fieldNum, f.Name, stringValue, err)
+		}
+	}
+	return nil
+}
+
+// validatorValue returns value passed to ScanField.Validator for value: variable pointed by value (i.e. int for *int).
+func validatorValue(value interface{}) interface{} {
+	if tv, ok := value.(timeValue); ok {
+		return *tv.value
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() {
+		return v.Elem().Interface()
+	}
+	return value
+}
+
+// validateConstraints checks that variable pointed by value satisfies constraints of f (such as Min, Max, MinLen, MaxLen, Pattern & AllowedValues).
+// It does nothing for value types for which constraints are not applicable.
+func (f *ScanField) validateConstraints(fieldNum int, stringValue string, value interface{}) error {
+	if inner, valid, isNull := nullValue(value); isNull { // sql.Null* is validated by its inner value
+		if !*valid {
+			return nil
+		}
+		value = inner
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr { // pointer to pointer is validated by variable it points to
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if isNumberKind(v.Kind()) {
+		if err := f.validateRange(fieldNum, stringValue, v); err != nil {
+			return err
+		}
+	}
+	if v.Kind() == reflect.String {
+		if err := f.validateLen(fieldNum, stringValue, v.String()); err != nil {
+			return err
+		}
+		if f.Pattern != nil && !f.Pattern.MatchString(v.String()) {
+			return scanErrorPatternMismatch(fieldNum, f.Name, stringValue, errors.New("value '"+v.String()+"' does not match pattern '"+f.Pattern.String()+"'."))
+		}
+		if err := f.validateAllowed(fieldNum, stringValue, v.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAllowed checks that s is one of f.AllowedValues (if any).
+func (f *ScanField) validateAllowed(fieldNum int, stringValue string, s string) error {
+	if len(f.AllowedValues) == 0 {
+		return nil
+	}
+	for _, a := range f.AllowedValues {
+		if s == a {
+			return nil
+		}
+	}
+	return scanErrorNotAllowedValue(fieldNum, f.Name, stringValue, errors.New("value '"+s+"' is not one of allowed values: '"+strings.Join(f.AllowedValues, "', '")+"'."))
+}
+
+// validateLen checks that length of s in runes is between f.MinLen & f.MaxLen.
+func (f *ScanField) validateLen(fieldNum int, stringValue string, s string) error {
+	if f.MinLen == 0 && f.MaxLen == 0 {
+		return nil
+	}
+	l := utf8.RuneCountInString(s)
+	if f.MinLen != 0 && l < f.MinLen {
+		return scanErrorLengthViolation(fieldNum, f.Name, stringValue, fmt.Errorf("value length %d is less than minimum %d.", l, f.MinLen))
+	}
+	if f.MaxLen != 0 && l > f.MaxLen {
+		return scanErrorLengthViolation(fieldNum, f.Name, stringValue, fmt.Errorf("value length %d is greater than maximum %d.", l, f.MaxLen))
+	}
+	return nil
+}
+
+// validateRange checks that number v is between f.Min & f.Max.
+func (f *ScanField) validateRange(fieldNum int, stringValue string, v reflect.Value) error {
+	if f.Min != nil {
+		c, ok := compareNumbers(v, reflect.ValueOf(f.Min))
+		if !ok {
+			return ScanError{FieldNum: fieldNum, FieldName: f.Name, Type: ScanErrorTypeIncompatibleType, SubError: errors.New("Min has non-numeric type."), Value: stringValue, ElementIndex: -1}
+		}
+		if c < 0 {
+			return scanErrorOutOfRange(fieldNum, f.Name, stringValue, fmt.Errorf("value %v is less than minimum %v.", v, f.Min))
+		}
+	}
+	if f.Max != nil {
+		c, ok := compareNumbers(v, reflect.ValueOf(f.Max))
+		if !ok {
+			return ScanError{FieldNum: fieldNum, FieldName: f.Name, Type: ScanErrorTypeIncompatibleType, SubError: errors.New("Max has non-numeric type."), Value: stringValue, ElementIndex: -1}
+		}
+		if c > 0 {
+			return scanErrorOutOfRange(fieldNum, f.Name, stringValue, fmt.Errorf("value %v is greater than maximum %v.", v, f.Max))
+		}
+	}
+	return nil
+}
+
+// isNumberKind checks if k is any of integer or float kinds.
+func isNumberKind(k reflect.Kind) bool {
+	return isIntKind(k) || isUintKind(k) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// isIntKind checks if k is any of signed integer kinds.
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+// isUintKind checks if k is any of unsigned integer kinds.
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// compareNumbers compares two numbers of any integer or float types.
+// It returns -1 if a < b, 0 if a == b and 1 if a > b.
+// Integers are compared exactly, if any of a & b is float then comparison is performed on float64.
+// ok is false if a or b is not a number.
+func compareNumbers(a, b reflect.Value) (c int, ok bool) {
+	ak, bk := a.Kind(), b.Kind()
+	if !isNumberKind(ak) || !isNumberKind(bk) {
+		return 0, false
+	}
+
+	switch {
+	case isIntKind(ak) && isIntKind(bk):
+		return compare(a.Int() < b.Int(), a.Int() > b.Int()), true
+	case isUintKind(ak) && isUintKind(bk):
+		return compare(a.Uint() < b.Uint(), a.Uint() > b.Uint()), true
+	case isIntKind(ak) && isUintKind(bk):
+		if a.Int() < 0 {
+			return -1, true
+		}
+		return compare(uint64(a.Int()) < b.Uint(), uint64(a.Int()) > b.Uint()), true
+	case isUintKind(ak) && isIntKind(bk):
+		if b.Int() < 0 {
+			return 1, true
+		}
+		return compare(a.Uint() < uint64(b.Int()), a.Uint() > uint64(b.Int())), true
+	}
+
+	af, bf := toFloat(a), toFloat(b)
+	return compare(af < bf, af > bf), true
+}
+
+// toFloat converts number v of any integer or float type to float64.
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isIntKind(v.Kind()):
+		return float64(v.Int())
+	case isUintKind(v.Kind()):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// compare converts result of comparison to -1, 0 or 1.
+func compare(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
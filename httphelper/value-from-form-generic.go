@@ -0,0 +1,18 @@
+package httphelper
+
+import "net/http"
+
+// ScanValue returns value of field with given name from Request.Form as T.
+// It is generic version of *FromForm functions (i.e. Int64FromForm), T may be any type supported by ScanFormData.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func ScanValue[T any](r *http.Request, name string) (value T, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// DecodeForm returns new T (which should be a struct) filled from Request.Form as BindForm does.
+// It obeys all BindForm rules, so struct field names are reported in errors.
+func DecodeForm[T any](r *http.Request) (value T, err error) {
+	err = BindForm(r, &value)
+	return
+}
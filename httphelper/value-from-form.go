@@ -1,163 +1,278 @@
 package httphelper
 
 import (
-	"errors"
-	"github.com/apaxa-io/strconvhelper"
+	"context"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 )
 
-// ScanErrorType define the type of error occurred while scanning form
-type ScanErrorType uint8
+// ScanFormData scans Request.Form for required fields and save its value.
+// Required fields names and variables to store values described by fields.
+// This function accept for each required field exactly one value in form. There is error if zero or more than one fields with requested name exists in form.
+// The only exception is slices (i.e. []int, []string): all values with requested name are parsed to such fields (slice will be empty if there is no such values).
+// Set field's Split to additionally split each value for slice field by separator (i.e. "1,2,3").
+// Set field's UUID to scan [16]byte (or named type with such underlying type) from single canonical UUID value (types implementing encoding.TextUnmarshaler, i.e. github.com/google/uuid, are supported anyway).
+// Fixed-size arrays (i.e. [3]int) are scanned as slices, but number of values should be exactly the same as length of array (error with type ScanErrorTypeValueCountMismatch is returned otherwise).
+// Optional fields (with Optional set) may be absent in form, in this case their variables leave untouched (or set to nil for pointer to pointer variables).
+// Fields with Default may be absent in form too, in this case Default assigns to their variables (if type of Default does not match type of variable, error with type ScanErrorTypeIncompatibleType is returned).
+// This function supports only following types of fields: [u]int[8/16/32/64], float32/64, complex64/128, bools, strings, time.Time, time.Duration, time.Weekday, time.Month, net.IP, net.IPNet, netip.Addr, netip.Prefix, url.URL, mail.Address, json.Number, json.RawMessage, big.Int, big.Float & big.Rat.
+// *int* will be parsed using strconv.ParseInt with base of 10 (or with field's Base if it is set).
+// Values with minus sign (including "-0") are invalid for unsigned fields, error message explicitly says so.
+// Range of int & uint depends on platform (32 or 64 bits), use ScanFormDataWithOptions with PortableIntSize to always check them against 32-bit range.
+// rune & byte are numbers too (int32 & uint8), set field's Char to scan them as single character.
+// []byte is a slice of numbers too, set field's Encoding to scan it from single encoded value (i.e. hex or base64).
+// floats will be parsed using strconv.ParseFloat with corresponding bit size (so "1e10", "-0.5", "NaN" & "Inf" are valid).
+// complexes will be parsed using strconv.ParseComplex with corresponding bit size (i.e. "3+4i").
+// for bools valid values are only "on" & "off" (case sensitive), use ScanFormDataWithOptions to change them (or set field's Checkbox to treat any present value as true and absence as false).
+// strings accepted as-is.
+// time.Time will be parsed using time.Parse with layout time.RFC3339 (use ScanTimeField to scan time with other layout), empty string is invalid time.
+// time.Duration will be parsed using time.ParseDuration (i.e. "30s", "1h30m"), set field's DurationUnit to scan it from integer number of units (i.e. seconds).
+// time.Weekday & time.Month accept either number (0 for Sunday, 1 for January) or English name in any case (i.e. "monday", "January").
+// net.IP will be parsed using net.ParseIP, net.IPNet - using net.ParseCIDR (network is stored, i.e. "192.168.1.1/24" results in 192.168.1.0/24).
+// netip.Addr & netip.Prefix will be parsed using netip.ParseAddr & netip.ParsePrefix.
+// url.URL will be parsed using url.Parse (use ScanFormDataWithOptions with AbsoluteURL option to accept only absolute URLs).
+// mail.Address will be parsed using mail.ParseAddress (i.e. "John <john@example.com>"), so it may be used to validate email.
+// json.Number will be stored as-is if it is a number in JSON grammar (so "NaN", "Inf", "+1", "01" & "0x10" are invalid) and it can be parsed as int64 or float64.
+// json.RawMessage will be stored as-is if it is a valid JSON (checked using json.Valid).
+// big.Int, big.Float & big.Rat will be parsed using their SetString methods (big.Int respects field's Base, big.Rat accepts both fractions & decimals, i.e. "1/3" & "0.25").
+// Pointers to pointers to any of supported types (i.e. **int) are also supported: new variable allocates and pointer to it stores (useful for nullable values).
+// Tri-state bools can be scanned to optional **bool or sql.NullBool field: true or false value sets true or false, absent field sets nil (invalid for sql.NullBool), combine with TreatEmptyAsAbsent option to treat empty value as absent too.
+// sql.NullString, sql.NullInt64, sql.NullInt32, sql.NullInt16, sql.NullByte, sql.NullFloat64, sql.NullBool & sql.NullTime are parsed as their inner type and marked valid (optional absent field is marked invalid).
+// Named types with numeric underlying type (i.e. type UserID int64) and uintptr are parsed as their underlying type.
+// Custom types can be scanned if they implement FormScanner interface (it has priority over build-in types).
+// Types implementing encoding.TextUnmarshaler (but not listed above) are also supported, they are parsed using UnmarshalText.
+// Types implementing encoding.BinaryUnmarshaler (but not encoding.TextUnmarshaler) are parsed using UnmarshalBinary, set field's Encoding to decode value before (i.e. from base64), in this case UnmarshalBinary has priority over UnmarshalText.
+// Uploaded files can be scanned to **multipart.FileHeader, they are looked up in Request.MultipartForm.File (so r.ParseMultipartForm should be performed before).
+// Variable of field is modified only if its value is parsed and satisfies all field's constraints (Min, MaxLen, Validator & etc.), so variable of failed field leaves untouched.
+// Returned error is always of type ScanError or nil.
+// Warning: r.ParseForm should be performed before calling this function (or use ScanFormDataWithOptions with AutoParseForm option).
+func ScanFormData(r *http.Request, fields ...ScanField) error {
+	var s Scanner
+	return s.Scan(r, fields...)
+}
 
-// Define available ScanError types
-const (
-	ScanErrorTypeNoSuchField       ScanErrorType = iota // There is not field in form with requested name
-	ScanErrorTypeMultipleValues                  = iota // There is more than 1 field in form with requested name
-	ScanErrorTypeIncompatibleValue               = iota // Value in form is incompatible with requested field type (i.e. trying to save "one" as int)
-	ScanErrorTypeIncompatibleType                = iota // Function unable to handle field with such type (i.e. truing to scan custom type)
-)
+// ScanFormDataWithOptions does the same as ScanFormData but uses given options instead of default ones.
+// If options.AutoParseForm is set then returned error may also be of type ParseFormError.
+// If options.ContinueOnError is set then returned error is of type ScanErrors instead of ScanError.
+// Use Scanner to reuse the same options across many requests.
+func ScanFormDataWithOptions(r *http.Request, options ScanOptions, fields ...ScanField) error {
+	return NewScanner(options).Scan(r, fields...)
+}
+
+// ScanPostFormData does the same as ScanFormData but scans only Request.PostForm (values from POST, PATCH & PUT body), so URL query parameters are ignored.
+// Warning: r.ParseForm should be performed before calling this function.
+func ScanPostFormData(r *http.Request, fields ...ScanField) error {
+	var options ScanOptions
+	return options.scanForm(r.PostForm, multipartFiles(r), fields)
+}
 
-// ScanError define error occurred while scanning form
-type ScanError struct {
-	FieldNum  int           // problem field number (beginning from 0)
-	FieldName string        // problem field name
-	Type      ScanErrorType // type of error
-	SubError  error         // child error, used to exactly describe problem with incompatible value (nil for other types of error)
+// ScanMultipartFormData does the same as ScanFormData but scans only values of multipart form (r.MultipartForm.Value), so URL query parameters are ignored.
+// Files are looked up in r.MultipartForm.File as usual.
+// If multipart form has not been parsed yet then r.ParseMultipartForm is called with given maxMemory, if it fails then error of type ParseFormError is returned.
+// Otherwise returned error is of type ScanError or nil.
+func ScanMultipartFormData(r *http.Request, maxMemory int64, fields ...ScanField) error {
+	if r.MultipartForm == nil {
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return ParseFormError{Err: err}
+		}
+	}
+	var options ScanOptions
+	return options.scanForm(r.MultipartForm.Value, r.MultipartForm.File, fields)
 }
 
-func scanErrorNoSuchField(fieldNum int, fieldName string) ScanError {
-	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeNoSuchField, SubError: nil}
+// ScanQueryData does the same as ScanFormData but scans only URL query parameters (r.URL.Query()), so request body is ignored.
+// It is not required to call r.ParseForm before calling this function.
+func ScanQueryData(r *http.Request, fields ...ScanField) error {
+	var options ScanOptions
+	return options.scanForm(r.URL.Query(), nil, fields)
 }
 
-func scanErrorMultipleValues(fieldNum int, fieldName string) ScanError {
-	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeMultipleValues, SubError: nil}
+// ScanHeaderData does the same as ScanFormData but scans request headers (r.Header) instead of form.
+// Header names are matched case insensitively (as they are case insensitive in HTTP).
+// Each header should be present exactly once (except headers scanned to slice fields).
+func ScanHeaderData(r *http.Request, fields ...ScanField) error {
+	options := ScanOptions{CaseInsensitiveNames: true}
+	return options.scanForm(url.Values(r.Header), nil, fields)
 }
 
-func scanErrorIncompatibleValue(fieldNum int, fieldName string, subError error) ScanError {
-	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeIncompatibleValue, SubError: subError}
+// ScanFormDataContext does the same as ScanFormData but checks ctx between fields and stops if ctx is done.
+// In this case ctx.Err() is returned (so returned error is of type ScanError, error returned by ctx.Err() or nil).
+// It is useful for scanning large forms (i.e. r.Context() may be passed to stop scanning if client disconnects).
+func ScanFormDataContext(ctx context.Context, r *http.Request, fields ...ScanField) error {
+	var options ScanOptions
+	return options.scanFormContext(ctx, r.Form, multipartFiles(r), fields)
 }
 
-func scanErrorIncompatibleType(fieldNum int, fieldName string) ScanError {
-	return ScanError{FieldNum: fieldNum, FieldName: fieldName, Type: ScanErrorTypeIncompatibleValue, SubError: nil}
+// ScanFormDataAll does the same as ScanFormData but it does not stop on first error.
+// It tries to scan all fields and returns all happened errors (in order of fields) as ScanErrors.
+// Successfully scanned fields are assigned even if other fields fail, so on error variables are partially modified (use ScanErrors.Failed to check which fields failed).
+// Variables of failed fields leave untouched: value is assigned only if it is parsed and satisfies all field's constraints (slice & array variables are replaced only if all their values are valid).
+// It returns nil if all fields scanned successfully.
+// Use ScanFormDataWithOptions with ContinueOnError option to combine this behaviour with other options.
+func ScanFormDataAll(r *http.Request, fields ...ScanField) error {
+	options := ScanOptions{ContinueOnError: true}
+	return options.scanForm(r.Form, multipartFiles(r), fields)
 }
 
-// Error Implement error interface for ScanError. It returns text representation of error.
-func (e ScanError) Error() string {
-	prefix := "Scan error in #" + string(e.FieldNum) + "field with name '" + e.FieldName + "': "
-	switch e.Type {
-	case ScanErrorTypeNoSuchField:
-		return prefix + "no field with such name."
-	case ScanErrorTypeMultipleValues:
-		return prefix + "there is more than 1 field with such name."
-	case ScanErrorTypeIncompatibleValue:
-		if e.SubError != nil {
-			return prefix + e.Error()
+// ScanFormDataPresent does the same as ScanFormData but also returns names of fields which are present in form (in order of fields).
+// It is useful for optional fields (i.e. to implement PATCH semantics: update only fields supplied by user).
+// If error happens then present is nil.
+func ScanFormDataPresent(r *http.Request, fields ...ScanField) (present []string, err error) {
+	var options ScanOptions
+	files := multipartFiles(r)
+	for i, field := range fields {
+		if err = options.scanField(r.Form, files, i, field); err != nil {
+			return nil, err
+		}
+		if options.present(r.Form, files, &field) {
+			present = append(present, field.Name)
 		}
-		return prefix + "unable to parse string to required type."
-	case ScanErrorTypeIncompatibleType:
-		return prefix + " type of this field is imcompatible with this function type."
 	}
-	return prefix + "unknown error"
+	return present, nil
 }
 
-// ScanField stores requested field name and variable to save value for ScanFormData.
-type ScanField struct {
-	Name  string      // field name
-	Value interface{} // variable to store value
+// parseForm calls r.ParseForm if AutoParseForm is set and form has not been parsed yet.
+// If MaxFormBytes is set then r.Body is limited before parsing, so too large body causes ParseFormError wrapping *http.MaxBytesError.
+func (o *ScanOptions) parseForm(r *http.Request) error {
+	if !o.AutoParseForm || r.Form != nil {
+		return nil
+	}
+	if o.MaxFormBytes > 0 && r.Body != nil {
+		r.Body = http.MaxBytesReader(nil, r.Body, o.MaxFormBytes)
+	}
+	if err := r.ParseForm(); err != nil {
+		return ParseFormError{Err: err}
+	}
+	return nil
 }
 
-const scanBoolTrueString = "on"
-const scanBoolFalseString = "off"
-
-// ScanFormData scans Request.Form for required fields and save its value.
-// Required fields names and variables to store values described by fields.
-// This function accept for each required field exactly one value in form. There is error if zero or more than one fields with requested name exists in form.
-// This function supports only following types of fields: [u]int[8/16/32/64], bools & strings.
-// *int* will be parsed using strconv.ParseInt with base of 10.
-// for bools valid values are only "on" & "off" (case sensitive).
-// strings accepted as-is.
-// Returned error is always of type ScanError or nil.
-// Warning: r.ParseForm should be performed before calling this function.
-func ScanFormData(r *http.Request, fields ...ScanField) error {
-	for i, field := range fields {
-		var stringValue string
-
-		if stringValues, ok := r.Form[field.Name]; ok && len(stringValues) == 1 {
-			stringValue = stringValues[0]
-		} else if !ok {
-			return scanErrorNoSuchField(i, field.Name)
-		} else {
-			return scanErrorMultipleValues(i, field.Name)
+// requestForm returns values of r to scan according to Source.
+// For FormSourceQueryFirst & FormSourceBodyFirst precedence is resolved per name, so all values with the same name are taken from the same source.
+func (o *ScanOptions) requestForm(r *http.Request) url.Values {
+	var first, second url.Values
+	switch o.Source {
+	case FormSourceQueryFirst:
+		first, second = r.URL.Query(), r.PostForm
+	case FormSourceBodyFirst:
+		first, second = r.PostForm, r.URL.Query()
+	default:
+		return r.Form
+	}
+	form := make(url.Values, len(first)+len(second))
+	for name, values := range first {
+		form[name] = values
+	}
+	for name, values := range second {
+		if _, exists := form[name]; !exists {
+			form[name] = values
 		}
+	}
+	return form
+}
 
-		var err error
-		switch value := field.Value.(type) {
-		case *int:
-			if *value, err = strconvhelper.ParseInt(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *int8:
-			if *value, err = strconvhelper.ParseInt8(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *int16:
-			if *value, err = strconvhelper.ParseInt16(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *int32:
-			if *value, err = strconvhelper.ParseInt32(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *int64:
-			if *value, err = strconvhelper.ParseInt64(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *uint:
-			if *value, err = strconvhelper.ParseUint(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *uint8:
-			if *value, err = strconvhelper.ParseUint8(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *uint16:
-			if *value, err = strconvhelper.ParseUint16(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *uint32:
-			if *value, err = strconvhelper.ParseUint32(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *uint64:
-			if *value, err = strconvhelper.ParseUint64(stringValue); err != nil {
-				return scanErrorIncompatibleValue(i, field.Name, err)
-			}
-		case *bool:
-			switch stringValue {
-			case scanBoolTrueString:
-				*value = true
-			case scanBoolFalseString:
-				*value = false
-			default:
-				return scanErrorIncompatibleValue(i, field.Name, errors.New("'"+stringValue+"' is not a valid bool value."))
-			}
-		case *string:
-			*value = stringValue
-		default:
-			return scanErrorIncompatibleType(i, field.Name)
-		}
+// multipartFiles returns uploaded files of r (nil if r.ParseMultipartForm has not been called or request is not multipart).
+func multipartFiles(r *http.Request) map[string][]*multipart.FileHeader {
+	if r.MultipartForm == nil {
+		return nil
 	}
-	return nil
+	return r.MultipartForm.File
+}
+
+// IntFromForm returns value of field with given name from Request.Form as int.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func IntFromForm(r *http.Request, name string) (value int, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Int8FromForm returns value of field with given name from Request.Form as int8.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Int8FromForm(r *http.Request, name string) (value int8, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
 }
 
-/*
+// Int16FromForm returns value of field with given name from Request.Form as int16.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Int16FromForm(r *http.Request, name string) (value int16, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Int32FromForm returns value of field with given name from Request.Form as int32.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Int32FromForm(r *http.Request, name string) (value int32, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Int64FromForm returns value of field with given name from Request.Form as int64.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
 func Int64FromForm(r *http.Request, name string) (value int64, err error) {
-	if svs, ok := r.Form[name]; ok && len(svs) == 1 {
-		value, err = strconv.ParseInt(svs[0], 10, 64)
-		return
-	} else {
-		err = errors.New("Not exactly one field with name " + name)
-		return
-	}
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// UintFromForm returns value of field with given name from Request.Form as uint.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func UintFromForm(r *http.Request, name string) (value uint, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Uint8FromForm returns value of field with given name from Request.Form as uint8.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Uint8FromForm(r *http.Request, name string) (value uint8, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Uint16FromForm returns value of field with given name from Request.Form as uint16.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Uint16FromForm(r *http.Request, name string) (value uint16, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Uint32FromForm returns value of field with given name from Request.Form as uint32.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Uint32FromForm(r *http.Request, name string) (value uint32, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Uint64FromForm returns value of field with given name from Request.Form as uint64.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Uint64FromForm(r *http.Request, name string) (value uint64, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Float32FromForm returns value of field with given name from Request.Form as float32.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Float32FromForm(r *http.Request, name string) (value float32, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// Float64FromForm returns value of field with given name from Request.Form as float64.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func Float64FromForm(r *http.Request, name string) (value float64, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// BoolFromForm returns value of field with given name from Request.Form as bool.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func BoolFromForm(r *http.Request, name string) (value bool, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
+}
+
+// StringFromForm returns value of field with given name from Request.Form as string.
+// It is a shortcut for ScanFormData with single field, so it obeys all ScanFormData rules and returned error is always of type ScanError or nil.
+func StringFromForm(r *http.Request, name string) (value string, err error) {
+	err = ScanFormData(r, ScanField{Name: name, Value: &value})
+	return
 }
-*/
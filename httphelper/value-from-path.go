@@ -0,0 +1,20 @@
+//go:build go1.22
+
+package httphelper
+
+import "net/http"
+
+// ScanPathData does the same as ScanFormData but scans path parameters matched by http.ServeMux pattern (using r.PathValue, i.e. "id" for "/users/{id}").
+// Field names (and aliases) are used as names of path parameters, empty path value is treated as absent.
+// Returned error is always of type ScanError or nil.
+func ScanPathData(r *http.Request, fields ...ScanField) error {
+	vals := make(map[string]string)
+	for _, field := range fields {
+		for _, name := range append([]string{field.Name}, field.Aliases...) {
+			if value := r.PathValue(name); value != "" {
+				vals[name] = value
+			}
+		}
+	}
+	return ScanPathValues(vals, fields...)
+}